@@ -0,0 +1,270 @@
+package alert
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+	"github.com/BohdanKyryliuk/golang/worker"
+)
+
+// invertPlaces is the number of decimal places of a rate derived by inverting a pair
+const invertPlaces = 10
+
+// Alert is a fired rule
+type Alert struct {
+	// ID identifies the alert across delivery attempts: the rule ID and the event ID
+	ID    string       `json:"id"`
+	Rule  Rule         `json:"rule"`
+	Base  string       `json:"base"`
+	Quote string       `json:"quote"`
+	Rate  money.Amount `json:"rate"`
+	// Reference is the rate before the crossing, or at the start of the window of a change rule
+	Reference     money.Amount `json:"reference"`
+	ChangePercent money.Amount `json:"change_percent"`
+	EventID       uint64       `json:"event_id"`
+	TriggeredAt   time.Time    `json:"triggered_at"`
+}
+
+// Notifier delivers fired alerts
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// pair is a currency pair of a rule
+type pair struct {
+	base, quote string
+}
+
+// sample is a rate of a pair at a point in time
+type sample struct {
+	at   time.Time
+	rate money.Amount
+}
+
+// Engine evaluates the rules against rate events and hands fired alerts to a notifier.
+// Each event is evaluated once, and a rule stays quiet for its cooldown after firing.
+// A rule follows the worker of its base, or the inverted rates of the worker of its
+// quote when no worker tracks the base, never both: the two come from independent
+// fetches, and comparing them would fire false change alerts.
+type Engine struct {
+	rules    *Rules
+	notifier Notifier
+	tracked  func() []string // Bases with a worker; nil to use the bases seen in events
+
+	mu          sync.Mutex
+	lastEventID uint64
+	seen        map[string]bool      // Bases of the evaluated events
+	samples     map[pair][]sample    // Recent rates of change rules, by the pair of the worker
+	lastFired   map[string]time.Time // By rule ID
+	deliveries  sync.WaitGroup
+}
+
+// EngineOption is a functional option for configuring an Engine
+type EngineOption func(*Engine)
+
+// WithTrackedBases sets the function returning the base currencies the workers track,
+// such as worker.Manager.GetCurrencies. Without it, a base counts as tracked once an
+// event of it was evaluated.
+func WithTrackedBases(tracked func() []string) EngineOption {
+	return func(e *Engine) {
+		e.tracked = tracked
+	}
+}
+
+// NewEngine creates an engine evaluating rules and delivering alerts with notifier
+func NewEngine(rules *Rules, notifier Notifier, opts ...EngineOption) *Engine {
+	e := &Engine{
+		rules:     rules,
+		notifier:  notifier,
+		seen:      make(map[string]bool),
+		samples:   make(map[pair][]sample),
+		lastFired: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Run evaluates events until ctx is done or events is closed, then waits for the
+// deliveries in progress. Deliveries are cancelled with ctx.
+func (e *Engine) Run(ctx context.Context, events <-chan worker.RateUpdated) {
+	defer e.deliveries.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			for _, alert := range e.Evaluate(event) {
+				e.deliveries.Add(1)
+				go func() {
+					defer e.deliveries.Done()
+					if err := e.notifier.Notify(ctx, alert); err != nil {
+						log.Printf("Error delivering alert %s: %v", alert.ID, err)
+					}
+				}()
+			}
+		}
+	}
+}
+
+// Evaluate returns the alerts fired by an event. Events that were already evaluated
+// fire nothing.
+func (e *Engine) Evaluate(event worker.RateUpdated) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if event.ID != 0 && event.ID <= e.lastEventID {
+		return nil
+	}
+	e.lastEventID = event.ID
+
+	now := event.FetchedAt
+	e.seen[event.Base] = true
+	tracked := e.trackedBases()
+	rules := e.rules.List()
+	e.record(event, rules, tracked)
+
+	var alerts []Alert
+	for _, rule := range rules {
+		src, inverted := source(rule, tracked)
+		change, ok := pairChange(event, src, inverted)
+		if !ok {
+			continue
+		}
+		if last, fired := e.lastFired[rule.ID]; fired && now.Sub(last) < rule.cooldown() {
+			continue
+		}
+
+		var reference money.Amount
+		switch rule.Kind {
+		case KindCrosses:
+			if !crosses(change.Old, change.New, rule.Threshold) {
+				continue
+			}
+			reference = change.Old
+		case KindChange:
+			reference, ok = e.windowStart(src, now.Add(-time.Duration(rule.Window)))
+			if ok && inverted {
+				reference = invert(reference)
+			}
+			if !ok || reference.IsZero() || worker.PercentChange(reference, change.New).Abs().Cmp(rule.ChangePercent) < 0 {
+				continue
+			}
+		}
+
+		e.lastFired[rule.ID] = now
+		alerts = append(alerts, Alert{
+			ID:            rule.ID + "-" + strconv.FormatUint(event.ID, 10),
+			Rule:          rule,
+			Base:          rule.Base,
+			Quote:         rule.Quote,
+			Rate:          change.New,
+			Reference:     reference,
+			ChangePercent: worker.PercentChange(reference, change.New),
+			EventID:       event.ID,
+			TriggeredAt:   now,
+		})
+	}
+	return alerts
+}
+
+// record keeps the rates of the workers followed by change rules for their windows,
+// as published by the workers; e.mu must be held
+func (e *Engine) record(event worker.RateUpdated, rules []Rule, tracked map[string]bool) {
+	cutoff := event.FetchedAt.Add(-MaxWindow)
+	for _, rule := range rules {
+		if rule.Kind != KindChange {
+			continue
+		}
+		src, _ := source(rule, tracked)
+		change, ok := pairChange(event, src, false)
+		if !ok {
+			continue
+		}
+
+		samples := e.samples[src]
+		if n := len(samples); n > 0 && samples[n-1].at.Equal(event.FetchedAt) {
+			continue // Recorded for another rule of the pair
+		}
+		for len(samples) > 0 && samples[0].at.Before(cutoff) {
+			samples = samples[1:]
+		}
+		e.samples[src] = append(samples, sample{at: event.FetchedAt, rate: change.New})
+	}
+}
+
+// trackedBases returns the base currencies with a worker; e.mu must be held
+func (e *Engine) trackedBases() map[string]bool {
+	if e.tracked == nil {
+		return e.seen
+	}
+	bases := make(map[string]bool)
+	for _, base := range e.tracked() {
+		bases[base] = true
+	}
+	return bases
+}
+
+// windowStart returns the oldest rate of a pair since the start of a window; e.mu must be held
+func (e *Engine) windowStart(p pair, start time.Time) (money.Amount, bool) {
+	for _, s := range e.samples[p] {
+		if !s.at.Before(start) {
+			return s.rate, true
+		}
+	}
+	return money.Amount{}, false
+}
+
+// source returns the pair of the worker whose events a rule follows, and whether its
+// rates must be inverted: the pair of the rule when its base is tracked, otherwise the
+// opposite pair
+func source(rule Rule, tracked map[string]bool) (pair, bool) {
+	if tracked[rule.Base] {
+		return pair{rule.Base, rule.Quote}, false
+	}
+	return pair{rule.Quote, rule.Base}, true
+}
+
+// pairChange returns the change of the rate of a pair in an event of the worker of
+// the pair's base, inverting it if asked
+func pairChange(event worker.RateUpdated, p pair, inverted bool) (worker.RateChange, bool) {
+	if event.Base != p.base {
+		return worker.RateChange{}, false
+	}
+	change, ok := event.Changes[p.quote]
+	if !ok || !inverted {
+		return change, ok
+	}
+	if change.New.IsZero() {
+		return worker.RateChange{}, false
+	}
+	return worker.RateChange{Old: invert(change.Old), New: invert(change.New)}, true
+}
+
+// invert returns 1/rate, or zero for a zero rate
+func invert(rate money.Amount) money.Amount {
+	inverted, err := money.New(1, 0).Div(rate, invertPlaces, money.HalfEven)
+	if err != nil {
+		return money.Amount{}
+	}
+	return inverted.Normalize()
+}
+
+// crosses reports whether a rate moving from old to new crosses threshold; a new
+// rate without an old one doesn't cross anything
+func crosses(old, new, threshold money.Amount) bool {
+	if old.IsZero() {
+		return false
+	}
+	return (old.Cmp(threshold) < 0 && new.Cmp(threshold) >= 0) ||
+		(old.Cmp(threshold) > 0 && new.Cmp(threshold) <= 0)
+}
@@ -0,0 +1,226 @@
+package alert
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+	"github.com/BohdanKyryliuk/golang/worker"
+)
+
+var eventStart = time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+
+// rateEvents returns events of rates of base in quote, one minute apart
+func rateEvents(base, quote string, rates ...string) []worker.RateUpdated {
+	events := make([]worker.RateUpdated, len(rates))
+	for i, rate := range rates {
+		change := worker.RateChange{New: money.MustParse(rate)}
+		if i > 0 {
+			change.Old = money.MustParse(rates[i-1])
+		}
+		events[i] = worker.RateUpdated{
+			ID:        uint64(i + 1),
+			Base:      base,
+			Changes:   map[string]worker.RateChange{quote: change},
+			FetchedAt: eventStart.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return events
+}
+
+// newTestEngine creates an engine with the given rules and no notifier
+func newTestEngine(t *testing.T, rules ...Rule) *Engine {
+	t.Helper()
+	set := NewRules()
+	for _, rule := range rules {
+		if _, err := set.Create(rule); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	return NewEngine(set, nil)
+}
+
+// firedAt evaluates the events and returns the indexes of the events that fired alerts
+func firedAt(engine *Engine, events []worker.RateUpdated) []int {
+	var fired []int
+	for i, event := range events {
+		if len(engine.Evaluate(event)) > 0 {
+			fired = append(fired, i)
+		}
+	}
+	return fired
+}
+
+func TestEngineRules(t *testing.T) {
+	crosses := Rule{ID: "crosses", Base: "EUR", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1.10"), Cooldown: Duration(time.Minute)}
+	change := Rule{ID: "change", Base: "GBP", Quote: "USD", Kind: KindChange, ChangePercent: money.MustParse("1"), Window: Duration(3 * time.Minute), Cooldown: Duration(5 * time.Minute)}
+
+	tests := []struct {
+		name   string
+		rule   Rule
+		events []worker.RateUpdated
+		want   []int
+	}{
+		{
+			name:   "crosses up and down",
+			rule:   crosses,
+			events: rateEvents("EUR", "USD", "1.09", "1.0999", "1.10", "1.105", "1.098", "1.097"),
+			want:   []int{2, 4},
+		},
+		{
+			name:   "starts above",
+			rule:   crosses,
+			events: rateEvents("EUR", "USD", "1.12", "1.11"),
+		},
+		{
+			name: "crosses on the inverted pair",
+			rule: crosses,
+			// 1/0.9174 = 1.0900, 1/0.9009 = 1.1100
+			events: rateEvents("USD", "EUR", "0.9174", "0.9009"),
+			want:   []int{1},
+		},
+		{
+			name: "cooldown",
+			rule: Rule{ID: "cooldown", Base: "EUR", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1.10"), Cooldown: Duration(5 * time.Minute)},
+			// Crosses at minutes 1, 2 and 6; the second crossing is within the cooldown
+			events: rateEvents("EUR", "USD", "1.09", "1.11", "1.09", "1.09", "1.09", "1.09", "1.11"),
+			want:   []int{1, 6},
+		},
+		{
+			name:   "change within the window",
+			rule:   change,
+			events: rateEvents("GBP", "USD", "1.2500", "1.2550", "1.2630", "1.2640"),
+			want:   []int{2},
+		},
+		{
+			name: "change spread beyond the window",
+			rule: change,
+			// +1.2% over 6 minutes, at most 0.6% within any 3 minutes
+			events: rateEvents("GBP", "USD", "1.2500", "1.2525", "1.2550", "1.2575", "1.2600", "1.2625", "1.2650"),
+		},
+		{
+			name:   "change down",
+			rule:   change,
+			events: rateEvents("GBP", "USD", "1.2500", "1.2370"),
+			want:   []int{1},
+		},
+		{
+			name:   "other pair",
+			rule:   change,
+			events: rateEvents("GBP", "EUR", "1.15", "1.30"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := firedAt(newTestEngine(t, tt.rule), tt.events)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Fired at events %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Fired at events %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineAlert(t *testing.T) {
+	engine := newTestEngine(t, Rule{ID: "eurusd", Base: "EUR", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1.10")})
+	events := rateEvents("EUR", "USD", "1.08", "1.1016")
+
+	engine.Evaluate(events[0])
+	alerts := engine.Evaluate(events[1])
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+	alert := alerts[0]
+	if alert.ID != "eurusd-2" || alert.Rate.String() != "1.1016" || alert.Reference.String() != "1.08" ||
+		alert.ChangePercent.String() != "2" || !alert.TriggeredAt.Equal(events[1].FetchedAt) {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+
+	// An event is only evaluated once
+	if alerts := engine.Evaluate(events[1]); len(alerts) != 0 {
+		t.Errorf("Expected a duplicate event to fire nothing, got %+v", alerts)
+	}
+}
+
+func TestEngineFollowsOneWorker(t *testing.T) {
+	change := Rule{ID: "change", Base: "GBP", Quote: "USD", Kind: KindChange, ChangePercent: money.MustParse("1"), Window: Duration(10 * time.Minute)}
+
+	// The GBP worker sees a steady rate while the USD worker, fetching at other
+	// times, is 1.5% off; comparing the two would fire on every event
+	gbp := rateEvents("GBP", "USD", "1.2500", "1.2500", "1.2500")
+	usd := rateEvents("USD", "GBP", "0.7880", "0.7880", "0.7880")
+	var events []worker.RateUpdated
+	for i := range gbp {
+		gbp[i].ID, usd[i].ID = uint64(2*i+1), uint64(2*i+2)
+		usd[i].FetchedAt = gbp[i].FetchedAt.Add(30 * time.Second)
+		events = append(events, gbp[i], usd[i])
+	}
+
+	tests := []struct {
+		name    string
+		tracked []string
+		events  []worker.RateUpdated
+		want    []int
+	}{
+		{name: "both workers", tracked: []string{"GBP", "USD"}, events: events},
+		{name: "both workers seen", events: events},
+		// Only the USD worker: its inverted rates are followed
+		{name: "quote worker", tracked: []string{"USD"}, events: rateEvents("USD", "GBP", "0.7880", "0.7880", "0.7750"), want: []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := NewRules()
+			if _, err := rules.Create(change); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			var opts []EngineOption
+			if tt.tracked != nil {
+				opts = append(opts, WithTrackedBases(func() []string { return tt.tracked }))
+			}
+			got := firedAt(NewEngine(rules, nil, opts...), tt.events)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Fired at events %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingNotifier records the alerts it is asked to deliver
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestEngineRun(t *testing.T) {
+	rules := NewRules()
+	if _, err := rules.Create(Rule{Base: "EUR", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1.10")}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	notifier := &recordingNotifier{}
+	engine := NewEngine(rules, notifier)
+
+	events := make(chan worker.RateUpdated, 3)
+	for _, event := range rateEvents("EUR", "USD", "1.09", "1.11", "1.12") {
+		events <- event
+	}
+	close(events)
+	engine.Run(context.Background(), events)
+
+	if len(notifier.alerts) != 1 || notifier.alerts[0].EventID != 2 {
+		t.Errorf("Expected one alert for event 2, got %+v", notifier.alerts)
+	}
+}
@@ -0,0 +1,333 @@
+// Package alert evaluates threshold rules against the rates produced by the workers
+// and delivers the alerts they fire as signed webhooks.
+package alert
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+	"github.com/BohdanKyryliuk/golang/worker"
+)
+
+// DefaultCooldown is how long a rule stays quiet after firing unless it sets its own cooldown
+const DefaultCooldown = 15 * time.Minute
+
+// MaxWindow is the longest window of a change rule
+const MaxWindow = 24 * time.Hour
+
+// ErrRuleNotFound is returned for operations on a rule that doesn't exist
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+// ErrRuleExists is returned when a rule is created with the ID of another rule
+var ErrRuleExists = errors.New("alert rule already exists")
+
+// Kind is the condition of a rule
+type Kind string
+
+// Rule kinds
+const (
+	// KindCrosses fires when the rate crosses Threshold in either direction
+	KindCrosses Kind = "crosses"
+	// KindChange fires when the rate moves by at least ChangePercent within Window
+	KindChange Kind = "change"
+)
+
+// Rule is a condition on the rate of a currency pair, e.g. "EUR/USD crosses 1.10"
+// or "GBP/USD moves more than 1% in 15 minutes"
+type Rule struct {
+	ID    string `json:"id"`
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Kind  Kind   `json:"kind"`
+	// Threshold is the rate a crosses rule watches
+	Threshold money.Amount `json:"threshold,omitzero"`
+	// ChangePercent is the move of a change rule in percent, in either direction
+	ChangePercent money.Amount `json:"change_percent,omitzero"`
+	// Window is the period over which a change rule measures the move
+	Window Duration `json:"window,omitzero"`
+	// Cooldown is how long the rule stays quiet after firing (default: DefaultCooldown)
+	Cooldown Duration `json:"cooldown,omitzero"`
+	// WebhookURL receives the alerts of the rule instead of the default webhook (optional)
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "15m" in JSON
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText encodes the duration as a string such as "15m0s"
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a duration string such as "15m"
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ValidationError reports an invalid rule field
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid alert rule " + e.Field + ": " + e.Message
+}
+
+// normalize upper-cases the currency codes of the rule
+func (r *Rule) normalize() {
+	r.Base = strings.ToUpper(strings.TrimSpace(r.Base))
+	r.Quote = strings.ToUpper(strings.TrimSpace(r.Quote))
+	r.Kind = Kind(strings.ToLower(string(r.Kind)))
+}
+
+// Validate checks that the rule can be evaluated
+func (r *Rule) Validate() error {
+	if !worker.IsCurrencyCode(r.Base) {
+		return &ValidationError{Field: "base", Message: "must be a 3-letter currency code"}
+	}
+	if !worker.IsCurrencyCode(r.Quote) || r.Quote == r.Base {
+		return &ValidationError{Field: "quote", Message: "must be a 3-letter currency code other than base"}
+	}
+	switch r.Kind {
+	case KindCrosses:
+		if r.Threshold.Sign() <= 0 {
+			return &ValidationError{Field: "threshold", Message: "must be positive"}
+		}
+	case KindChange:
+		if r.ChangePercent.Sign() <= 0 {
+			return &ValidationError{Field: "change_percent", Message: "must be positive"}
+		}
+		if r.Window <= 0 || time.Duration(r.Window) > MaxWindow {
+			return &ValidationError{Field: "window", Message: "must be positive and at most " + MaxWindow.String()}
+		}
+	default:
+		return &ValidationError{Field: "kind", Message: "must be crosses or change"}
+	}
+	if r.Cooldown < 0 {
+		return &ValidationError{Field: "cooldown", Message: "must not be negative"}
+	}
+	if r.WebhookURL != "" && !strings.HasPrefix(r.WebhookURL, "http://") && !strings.HasPrefix(r.WebhookURL, "https://") {
+		return &ValidationError{Field: "webhook_url", Message: "must be an http or https URL"}
+	}
+	return nil
+}
+
+// cooldown returns how long the rule stays quiet after firing
+func (r *Rule) cooldown() time.Duration {
+	if r.Cooldown == 0 {
+		return DefaultCooldown
+	}
+	return time.Duration(r.Cooldown)
+}
+
+// Rules holds the alert rules, optionally persisted to a JSON file
+type Rules struct {
+	path  string // Saved on every change if set
+	rules map[string]Rule
+	order []string // Rule IDs in the order they were added
+	mu    sync.RWMutex
+}
+
+// NewRules creates an in-memory set of rules
+func NewRules() *Rules {
+	return &Rules{rules: make(map[string]Rule)}
+}
+
+// LoadRules loads rules from a JSON array in a file and saves every change back to it;
+// a missing file gives an empty set
+func LoadRules(path string) (*Rules, error) {
+	rules := NewRules()
+	rules.path = path
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading alert rules: %w", err)
+	}
+
+	var list []Rule
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("loading alert rules: %w", err)
+	}
+	for _, rule := range list {
+		if _, err := rules.add(rule); err != nil {
+			return nil, fmt.Errorf("loading alert rules: %w", err)
+		}
+	}
+	return rules, nil
+}
+
+// List returns all rules in the order they were added
+func (s *Rules) List() []Rule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Rule, 0, len(s.order))
+	for _, id := range s.order {
+		list = append(list, s.rules[id])
+	}
+	return list
+}
+
+// Get returns a rule by ID
+func (s *Rules) Get(id string) (Rule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule, ok := s.rules[id]
+	if !ok {
+		return Rule{}, ErrRuleNotFound
+	}
+	return rule, nil
+}
+
+// Create validates and adds a rule, generating its ID if empty
+func (s *Rules) Create(rule Rule) (Rule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, err := s.prepare(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	rules := maps.Clone(s.rules)
+	rules[rule.ID] = rule
+	if err := s.commit(rules, append(slices.Clone(s.order), rule.ID)); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Update validates and replaces the rule with the given ID
+func (s *Rules) Update(id string, rule Rule) (Rule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rules[id]; !ok {
+		return Rule{}, ErrRuleNotFound
+	}
+	rule.ID = id
+	rule.normalize()
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	rules := maps.Clone(s.rules)
+	rules[id] = rule
+	if err := s.commit(rules, s.order); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Delete removes the rule with the given ID
+func (s *Rules) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rules[id]; !ok {
+		return ErrRuleNotFound
+	}
+	rules := maps.Clone(s.rules)
+	delete(rules, id)
+	order := slices.DeleteFunc(slices.Clone(s.order), func(other string) bool { return other == id })
+	return s.commit(rules, order)
+}
+
+// add validates and adds a rule without saving it; s.mu must be held
+func (s *Rules) add(rule Rule) (Rule, error) {
+	rule, err := s.prepare(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	s.rules[rule.ID] = rule
+	s.order = append(s.order, rule.ID)
+	return rule, nil
+}
+
+// prepare normalizes and validates a new rule and assigns its ID; s.mu must be held
+func (s *Rules) prepare(rule Rule) (Rule, error) {
+	rule.normalize()
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	if rule.ID == "" {
+		rule.ID = newRuleID()
+	}
+	if _, ok := s.rules[rule.ID]; ok {
+		return Rule{}, fmt.Errorf("%w: %s", ErrRuleExists, rule.ID)
+	}
+	return rule, nil
+}
+
+// commit saves the rules and then makes them the current ones, so that the rules in
+// memory never differ from the file after a failed save; s.mu must be held
+func (s *Rules) commit(rules map[string]Rule, order []string) error {
+	if err := s.save(rules, order); err != nil {
+		return err
+	}
+	s.rules, s.order = rules, order
+	return nil
+}
+
+// save writes rules in order to the file, replacing it atomically; s.mu must be held
+func (s *Rules) save(rules map[string]Rule, order []string) error {
+	if s.path == "" {
+		return nil
+	}
+
+	list := make([]Rule, 0, len(order))
+	for _, id := range order {
+		list = append(list, rules[id])
+	}
+	content, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("saving alert rules: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("saving alert rules: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving alert rules: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving alert rules: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("saving alert rules: %w", err)
+	}
+	return nil
+}
+
+// newRuleID returns a random rule ID
+func newRuleID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
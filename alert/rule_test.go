@@ -0,0 +1,129 @@
+package alert
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+func TestRuleValidate(t *testing.T) {
+	valid := Rule{Base: "eur", Quote: "usd", Kind: KindCrosses, Threshold: money.MustParse("1.10")}
+
+	tests := []struct {
+		name   string
+		modify func(*Rule)
+		field  string
+	}{
+		{name: "valid", modify: func(r *Rule) {}},
+		{name: "invalid base", modify: func(r *Rule) { r.Base = "EURO" }, field: "base"},
+		{name: "same quote", modify: func(r *Rule) { r.Quote = "EUR" }, field: "quote"},
+		{name: "unknown kind", modify: func(r *Rule) { r.Kind = "above" }, field: "kind"},
+		{name: "missing threshold", modify: func(r *Rule) { r.Threshold = money.Amount{} }, field: "threshold"},
+		{name: "missing change", modify: func(r *Rule) { r.Kind = KindChange; r.Window = Duration(time.Minute) }, field: "change_percent"},
+		{name: "missing window", modify: func(r *Rule) { r.Kind = KindChange; r.ChangePercent = money.MustParse("1") }, field: "window"},
+		{name: "negative cooldown", modify: func(r *Rule) { r.Cooldown = Duration(-time.Minute) }, field: "cooldown"},
+		{name: "invalid webhook", modify: func(r *Rule) { r.WebhookURL = "ftp://example.com" }, field: "webhook_url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := valid
+			tt.modify(&rule)
+			_, err := NewRules().Create(rule)
+
+			var validationErr *ValidationError
+			if tt.field == "" && err != nil {
+				t.Errorf("Create() error = %v", err)
+			}
+			if tt.field != "" && (!errors.As(err, &validationErr) || validationErr.Field != tt.field) {
+				t.Errorf("Expected ValidationError on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestRulesPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	created, err := rules.Create(Rule{Base: "eur", Quote: "usd", Kind: KindCrosses, Threshold: money.MustParse("1.10")})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if created.ID == "" || created.Base != "EUR" {
+		t.Errorf("Expected a generated ID and upper-case codes, got %+v", created)
+	}
+	change := Rule{ID: "gbp-moves", Base: "GBP", Quote: "USD", Kind: KindChange, ChangePercent: money.MustParse("1"), Window: Duration(15 * time.Minute)}
+	if _, err := rules.Create(change); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	change.ChangePercent = money.MustParse("2")
+	if _, err := rules.Update("gbp-moves", change); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := rules.Delete(created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	reloaded, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	list := reloaded.List()
+	if len(list) != 1 || list[0].ID != "gbp-moves" || list[0].ChangePercent.String() != "2" || list[0].Window != Duration(15*time.Minute) {
+		t.Errorf("Unexpected reloaded rules: %+v", list)
+	}
+
+	if _, err := reloaded.Get(created.ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("Get() of a deleted rule error = %v", err)
+	}
+	if _, err := reloaded.Update(created.ID, change); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("Update() of a deleted rule error = %v", err)
+	}
+	if err := reloaded.Delete(created.ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("Delete() of a deleted rule error = %v", err)
+	}
+}
+
+func TestRulesFailedSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "alerts")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadRules(filepath.Join(dir, "alerts.json"))
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	rule := Rule{ID: "eur-parity", Base: "EUR", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1")}
+	if _, err := rules.Create(rule); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := rules.Create(rule); !errors.Is(err, ErrRuleExists) {
+		t.Errorf("Create() of a duplicate ID error = %v, want ErrRuleExists", err)
+	}
+
+	// Changes that can't be saved must not take effect
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rules.Create(Rule{Base: "GBP", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1.3")}); err == nil {
+		t.Error("Create() error = nil")
+	}
+	updated := rule
+	updated.Threshold = money.MustParse("2")
+	if _, err := rules.Update(rule.ID, updated); err == nil {
+		t.Error("Update() error = nil")
+	}
+	if err := rules.Delete(rule.ID); err == nil {
+		t.Error("Delete() error = nil")
+	}
+	if list := rules.List(); len(list) != 1 || list[0].Threshold.String() != "1" {
+		t.Errorf("Expected the rules to be unchanged, got %+v", list)
+	}
+}
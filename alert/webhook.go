@@ -0,0 +1,244 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Webhook delivery defaults
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+)
+
+// Headers of webhook requests
+const (
+	HeaderID        = "X-Alert-ID"
+	HeaderTimestamp = "X-Alert-Timestamp"
+	HeaderSignature = "X-Alert-Signature"
+)
+
+// StatusError reports a webhook response with an unexpected status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook responded with HTTP %d", e.StatusCode)
+}
+
+// temporary reports whether retrying the delivery might succeed
+func (e *StatusError) temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// DeliveryError reports an alert that couldn't be delivered
+type DeliveryError struct {
+	URL      string
+	Attempts int
+	Err      error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("delivering alert to %s failed after %d attempts: %v", e.URL, e.Attempts, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// deadLetter is a line of the dead-letter file
+type deadLetter struct {
+	Alert    Alert     `json:"alert"`
+	URL      string    `json:"url"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// Webhook delivers alerts as JSON POST requests signed with HMAC-SHA256. Failed
+// deliveries are retried with exponential backoff; alerts that still fail are
+// appended to the dead-letter file.
+type Webhook struct {
+	url            string
+	secret         []byte
+	client         *http.Client
+	maxAttempts    int
+	backoff        time.Duration
+	deadLetterFile string
+	mu             sync.Mutex // Serializes writes to the dead-letter file
+}
+
+// WebhookOption is a functional option for configuring a Webhook
+type WebhookOption func(*Webhook)
+
+// WithHTTPClient sets the HTTP client used to deliver alerts
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.client = client
+	}
+}
+
+// WithRetries sets the number of delivery attempts and the delay before the first retry
+func WithRetries(maxAttempts int, backoff time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		if maxAttempts > 0 {
+			w.maxAttempts = maxAttempts
+		}
+		if backoff > 0 {
+			w.backoff = backoff
+		}
+	}
+}
+
+// WithDeadLetterFile sets the file alerts that couldn't be delivered are appended to as JSON lines
+func WithDeadLetterFile(path string) WebhookOption {
+	return func(w *Webhook) {
+		w.deadLetterFile = path
+	}
+}
+
+// NewWebhook creates a webhook posting alerts to url, signed with secret. Rules with
+// their own webhook URL are delivered there instead.
+func NewWebhook(url, secret string, opts ...WebhookOption) *Webhook {
+	w := &Webhook{
+		url:         url,
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Notify delivers an alert, retrying temporary failures until ctx is done
+func (w *Webhook) Notify(ctx context.Context, alert Alert) error {
+	url := alert.Rule.WebhookURL
+	if url == "" {
+		url = w.url
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	var attempts int
+	for attempts = 1; ; attempts++ {
+		err = w.post(ctx, url, alert.ID, body)
+		if err == nil {
+			return nil
+		}
+		var statusErr *StatusError
+		if attempts == w.maxAttempts || (errors.As(err, &statusErr) && !statusErr.temporary()) {
+			break
+		}
+		if waitErr := sleep(ctx, w.backoff<<(attempts-1)); waitErr != nil {
+			err = fmt.Errorf("%w (last error: %v)", waitErr, err)
+			break
+		}
+	}
+
+	deliveryErr := &DeliveryError{URL: url, Attempts: attempts, Err: err}
+	w.writeDeadLetter(alert, deliveryErr)
+	return deliveryErr
+}
+
+// post sends one delivery attempt
+func (w *Webhook) post(ctx context.Context, url, id string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(w.secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// writeDeadLetter appends an alert that couldn't be delivered to the dead-letter file
+func (w *Webhook) writeDeadLetter(alert Alert, deliveryErr *DeliveryError) {
+	if w.deadLetterFile == "" {
+		return
+	}
+	line, err := json.Marshal(deadLetter{
+		Alert:    alert,
+		URL:      deliveryErr.URL,
+		Attempts: deliveryErr.Attempts,
+		Error:    deliveryErr.Err.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Error encoding dead letter of alert %s: %v", alert.ID, err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.OpenFile(w.deadLetterFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err == nil {
+		_, err = f.Write(append(line, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("Error writing dead letter of alert %s: %v", alert.ID, err)
+	}
+}
+
+// Sign returns the signature of a webhook request: "sha256=" and the hex encoded
+// HMAC-SHA256 of the timestamp, a dot and the body
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of a webhook request, for receivers
+func Verify(secret []byte, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
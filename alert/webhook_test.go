@@ -0,0 +1,166 @@
+package alert
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+const testSecret = "s3cret"
+
+func testAlert() Alert {
+	return Alert{
+		ID:          "eurusd-2",
+		Rule:        Rule{ID: "eurusd", Base: "EUR", Quote: "USD", Kind: KindCrosses, Threshold: money.MustParse("1.10")},
+		Base:        "EUR",
+		Quote:       "USD",
+		Rate:        money.MustParse("1.1016"),
+		Reference:   money.MustParse("1.08"),
+		EventID:     2,
+		TriggeredAt: eventStart,
+	}
+}
+
+// newReceiver serves webhooks, answering with the statuses in order and then 200
+func newReceiver(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if !Verify([]byte(testSecret), timestamp, body, r.Header.Get(HeaderSignature)) {
+			t.Errorf("Invalid signature %q", r.Header.Get(HeaderSignature))
+		}
+		var alert Alert
+		if err := json.Unmarshal(body, &alert); err != nil || alert.ID != r.Header.Get(HeaderID) {
+			t.Errorf("Unexpected body %s for alert %s", body, r.Header.Get(HeaderID))
+		}
+
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestWebhookNotify(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		wantCalls  int32
+		wantErr    bool
+		deadLetter bool
+	}{
+		{name: "delivered", wantCalls: 1},
+		{name: "retried", statuses: []int{503, 429}, wantCalls: 3},
+		{name: "attempts exhausted", statuses: []int{500, 502, 503}, wantCalls: 3, wantErr: true, deadLetter: true},
+		{name: "rejected", statuses: []int{400}, wantCalls: 1, wantErr: true, deadLetter: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newReceiver(t, tt.statuses...)
+			deadLetterFile := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+			webhook := NewWebhook(server.URL, testSecret, WithRetries(3, time.Millisecond), WithDeadLetterFile(deadLetterFile))
+
+			err := webhook.Notify(context.Background(), testAlert())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("Receiver called %d times, want %d", calls.Load(), tt.wantCalls)
+			}
+
+			var deliveryErr *DeliveryError
+			if tt.wantErr && (!errors.As(err, &deliveryErr) || deliveryErr.Attempts != int(tt.wantCalls)) {
+				t.Errorf("Expected a DeliveryError after %d attempts, got %v", tt.wantCalls, err)
+			}
+
+			lines := readDeadLetters(t, deadLetterFile)
+			if tt.deadLetter != (len(lines) == 1) {
+				t.Fatalf("Unexpected dead letters: %+v", lines)
+			}
+			if tt.deadLetter && (lines[0].Alert.ID != "eurusd-2" || lines[0].Attempts != int(tt.wantCalls) || lines[0].URL != server.URL) {
+				t.Errorf("Unexpected dead letter: %+v", lines[0])
+			}
+		})
+	}
+}
+
+func TestWebhookRuleURL(t *testing.T) {
+	fallback, fallbackCalls := newReceiver(t)
+	own, ownCalls := newReceiver(t)
+
+	alert := testAlert()
+	alert.Rule.WebhookURL = own.URL
+	if err := NewWebhook(fallback.URL, testSecret).Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if ownCalls.Load() != 1 || fallbackCalls.Load() != 0 {
+		t.Errorf("Expected the rule's webhook to be called, got %d and %d calls", ownCalls.Load(), fallbackCalls.Load())
+	}
+}
+
+func TestWebhookCancelled(t *testing.T) {
+	server, _ := newReceiver(t, 503, 503)
+	deadLetterFile := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	webhook := NewWebhook(server.URL, testSecret, WithRetries(3, time.Hour), WithDeadLetterFile(deadLetterFile))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := webhook.Notify(ctx, testAlert()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the delivery to end with the context, got %v", err)
+	}
+	if lines := readDeadLetters(t, deadLetterFile); len(lines) != 1 {
+		t.Errorf("Expected the cancelled alert in the dead-letter file, got %+v", lines)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"eurusd-2"}`)
+	signature := Sign([]byte(testSecret), 1733479200, body)
+	if !Verify([]byte(testSecret), 1733479200, body, signature) {
+		t.Error("Expected the signature to verify")
+	}
+	if Verify([]byte(testSecret), 1733479201, body, signature) || Verify([]byte("other"), 1733479200, body, signature) {
+		t.Error("Expected a different timestamp or secret to fail verification")
+	}
+}
+
+// readDeadLetters returns the lines of a dead-letter file
+func readDeadLetters(t *testing.T, path string) []deadLetter {
+	t.Helper()
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	var lines []deadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line deadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Invalid dead letter %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
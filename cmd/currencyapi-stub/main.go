@@ -0,0 +1,103 @@
+// Command currencyapi-stub serves a local CurrencyAPI-compatible API from fixture files,
+// so the web server and workers can run end-to-end without network access.
+//
+// Usage:
+//
+//	go run ./cmd/currencyapi-stub -addr :8081 -fixtures ./cmd/currencyapi-stub/fixtures
+//	CURRENCY_API_KEY=stub CURRENCY_API_BASE_URL=http://localhost:8081/v3/ go run .
+//
+// Faults can be injected at start with -fault or at runtime:
+//
+//	curl -X POST "http://localhost:8081/_stub/fault?mode=429"
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	fixtures := flag.String("fixtures", "cmd/currencyapi-stub/fixtures", "directory with fixture files")
+	base := flag.String("base", "USD", "base currency of the fixture rates")
+	apiKey := flag.String("api-key", "", "API key clients must send (any non-empty key if empty)")
+	faultName := flag.String("fault", "none", "fault to inject: none, 401, 429 or quota")
+	flag.Parse()
+
+	fault, err := currencyapitest.ParseFault(*faultName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fake, err := currencyapitest.LoadFixtures(*fixtures, *base)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	stub := currencyapitest.NewServer(fake, currencyapitest.ServerOptions{
+		APIKey: *apiKey,
+		Fault:  fault,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/v3/", http.StripPrefix("/v3", stub))
+	mux.HandleFunc("/_stub/fault", faultHandler(stub))
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: logRequests(mux),
+	}
+
+	// Handle graceful shutdown
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		<-sigCh
+
+		log.Println("Shutting down stub server...")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	log.Printf("CurrencyAPI stub listening on %s (base URL http://localhost%s/v3/)", *addr, *addr)
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// faultHandler reports the injected fault on GET and changes it on POST (?mode=none|401|429|quota)
+func faultHandler(stub *currencyapitest.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			fault, err := currencyapitest.ParseFault(r.URL.Query().Get("mode"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			stub.SetFault(fault)
+			log.Printf("Injected fault: %q", fault)
+		}
+
+		fmt.Fprintf(w, "fault=%q\n", stub.Fault())
+	}
+}
+
+// logRequests logs every request served by the stub
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+)
+
+// TestFixtures serves the bundled fixtures to a real client
+func TestFixtures(t *testing.T) {
+	fake, err := currencyapitest.LoadFixtures("fixtures", "USD")
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+
+	server := httptest.NewServer(http.StripPrefix("/v3", currencyapitest.NewServer(fake, currencyapitest.ServerOptions{})))
+	defer server.Close()
+
+	client, _ := currencyapi.NewHttpApiClient("stub", currencyapi.WithBaseURL(server.URL+"/v3/"))
+	ctx := context.Background()
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Quotas.Month.Total == 0 {
+		t.Error("Expected quota from status.json")
+	}
+
+	currencies, err := client.Currencies(ctx, nil)
+	if err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+	if currencies.Data["JPY"].DecimalDigits != 0 {
+		t.Errorf("Expected JPY from currencies.json, got %+v", currencies.Data["JPY"])
+	}
+
+	latest, err := client.Latest(ctx, &currencyapi.LatestParams{BaseCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Meta.LastUpdatedAt != "2025-12-05T23:59:59Z" {
+		t.Errorf("Expected timestamp from latest.json, got %q", latest.Meta.LastUpdatedAt)
+	}
+	if len(latest.Data) != len(currencies.Data) {
+		t.Errorf("Expected rates for all %d currencies, got %d", len(currencies.Data), len(latest.Data))
+	}
+
+	historical, err := client.Historical(ctx, &currencyapi.HistoricalParams{Date: "2025-01-02", Currencies: []string{"EUR"}})
+	if err != nil {
+		t.Fatalf("Historical() error = %v", err)
+	}
+	if historical.Data["EUR"].Value.String() != "0.970405" {
+		t.Errorf("Expected EUR rate from historical fixture, got %+v", historical.Data["EUR"])
+	}
+}
+
+func TestFaultHandler(t *testing.T) {
+	stub := currencyapitest.NewServer(currencyapitest.NewFakeClient("USD", nil), currencyapitest.ServerOptions{})
+	handler := faultHandler(stub)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/_stub/fault?mode=quota", nil))
+	if rec.Code != http.StatusOK || stub.Fault() != currencyapitest.FaultQuotaExceeded {
+		t.Errorf("Expected quota fault, got %d %q", rec.Code, stub.Fault())
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/_stub/fault?mode=teapot", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown fault, got %d", rec.Code)
+	}
+}
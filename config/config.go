@@ -3,8 +3,11 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,8 +17,26 @@ import (
 type CurrencyAPIConfig struct {
 	APIKey  string
 	Timeout time.Duration
+	// BaseURL overrides the API endpoint, e.g. to point at a local stub (optional)
+	BaseURL string
+	// RateLimitPerMinute caps outgoing API requests per minute (0 disables the limiter)
+	RateLimitPerMinute int
+	// QuotaReserve is the number of monthly requests kept for background workers (0 disables the guard)
+	QuotaReserve int
+	// CacheTTL is how long latest rates are cached (0 disables the cache)
+	CacheTTL time.Duration
+	// Fallbacks lists the providers used in order when CurrencyAPI fails (ecb, openexchangerates)
+	Fallbacks []string
+	// OpenExchangeRatesAppID is the App ID of the openexchangerates fallback
+	OpenExchangeRatesAppID string
 }
 
+// Fallback providers supported by CURRENCY_API_FALLBACKS
+const (
+	FallbackECB               = "ecb"
+	FallbackOpenExchangeRates = "openexchangerates"
+)
+
 // ConfigError represents a configuration-related error
 type ConfigError struct {
 	Field   string
@@ -57,10 +78,219 @@ func LoadCurrencyAPIConfig(opts *LoadOptions) (*CurrencyAPIConfig, error) {
 	// Default timeout
 	timeout := 15 * time.Second
 
-	return &CurrencyAPIConfig{
-		APIKey:  apiKey,
-		Timeout: timeout,
-	}, nil
+	rateLimit, err := intFromEnv("CURRENCY_API_RATE_LIMIT")
+	if err != nil {
+		return nil, err
+	}
+
+	quotaReserve, err := intFromEnv("CURRENCY_API_QUOTA_RESERVE")
+	if err != nil {
+		return nil, err
+	}
+
+	// Default cache TTL
+	cacheTTL := time.Minute
+	if value := os.Getenv("CURRENCY_API_CACHE_TTL"); value != "" {
+		cacheTTL, err = time.ParseDuration(value)
+		if err != nil || cacheTTL < 0 {
+			return nil, &ConfigError{
+				Field:   "CURRENCY_API_CACHE_TTL",
+				Message: "must be a non-negative duration such as 30s or 5m",
+			}
+		}
+	}
+
+	cfg := &CurrencyAPIConfig{
+		APIKey:                 apiKey,
+		Timeout:                timeout,
+		BaseURL:                os.Getenv("CURRENCY_API_BASE_URL"),
+		RateLimitPerMinute:     rateLimit,
+		QuotaReserve:           quotaReserve,
+		CacheTTL:               cacheTTL,
+		OpenExchangeRatesAppID: os.Getenv("OPENEXCHANGERATES_APP_ID"),
+	}
+	for _, name := range strings.Split(os.Getenv("CURRENCY_API_FALLBACKS"), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			cfg.Fallbacks = append(cfg.Fallbacks, name)
+		}
+	}
+
+	if err := cfg.validateFallbacks(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validateFallbacks checks that every fallback provider is known and configured
+func (c *CurrencyAPIConfig) validateFallbacks() error {
+	for _, name := range c.Fallbacks {
+		switch name {
+		case FallbackECB:
+		case FallbackOpenExchangeRates:
+			if c.OpenExchangeRatesAppID == "" {
+				return &ConfigError{
+					Field:   "OPENEXCHANGERATES_APP_ID",
+					Message: "required by the openexchangerates fallback",
+				}
+			}
+		default:
+			return &ConfigError{
+				Field:   "CURRENCY_API_FALLBACKS",
+				Message: "unknown provider " + strconv.Quote(name) + ", expected ecb or openexchangerates",
+			}
+		}
+	}
+	return nil
+}
+
+// intFromEnv reads an optional non-negative integer environment variable
+func intFromEnv(name string) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, &ConfigError{
+			Field:   name,
+			Message: "must be a non-negative integer",
+		}
+	}
+	return n, nil
+}
+
+// OfflineMode reports whether the application should use the in-memory fake
+// currency API instead of the real service (CURRENCY_API_OFFLINE=true)
+func OfflineMode() bool {
+	// Try to load default .env file, ignore if not found
+	_ = godotenv.Load()
+
+	offline, _ := strconv.ParseBool(os.Getenv("CURRENCY_API_OFFLINE"))
+	return offline
+}
+
+// RatesStoreDir returns the directory where the background workers persist the
+// latest rates (RATES_STORE_DIR); empty keeps them in memory only
+func RatesStoreDir() string {
+	// Try to load default .env file, ignore if not found
+	_ = godotenv.Load()
+
+	return os.Getenv("RATES_STORE_DIR")
+}
+
+// AlertConfig holds configuration for threshold alerts
+type AlertConfig struct {
+	// WebhookURL receives the alerts of rules without their own webhook URL
+	WebhookURL string
+	// WebhookSecret signs the webhook requests
+	WebhookSecret string
+	// RulesFile holds the alert rules as JSON and is updated by the alert endpoints (optional)
+	RulesFile string
+	// DeadLetterFile collects the alerts that couldn't be delivered (optional)
+	DeadLetterFile string
+}
+
+// LoadAlertConfig loads alert configuration from environment variables.
+// It returns nil if alerts are disabled, i.e. ALERT_WEBHOOK_URL is not set.
+func LoadAlertConfig() (*AlertConfig, error) {
+	// Try to load default .env file, ignore if not found
+	_ = godotenv.Load()
+
+	cfg := &AlertConfig{
+		WebhookURL:     os.Getenv("ALERT_WEBHOOK_URL"),
+		WebhookSecret:  os.Getenv("ALERT_WEBHOOK_SECRET"),
+		RulesFile:      os.Getenv("ALERT_RULES_FILE"),
+		DeadLetterFile: os.Getenv("ALERT_DEAD_LETTER_FILE"),
+	}
+	if cfg.WebhookURL == "" {
+		return nil, nil
+	}
+	if cfg.WebhookSecret == "" {
+		return nil, &ConfigError{
+			Field:   "ALERT_WEBHOOK_SECRET",
+			Message: "required to sign alert webhooks",
+		}
+	}
+	return cfg, nil
+}
+
+// API key scopes
+const (
+	// ScopeRatesRead allows reading currency data and rates
+	ScopeRatesRead = "rates:read"
+	// ScopeAdmin allows everything, including managing the workers and the alert rules
+	ScopeAdmin = "admin"
+)
+
+// APIKey is a key clients of the server authenticate with
+type APIKey struct {
+	// Name identifies the key in logs and usage reports
+	Name   string   `json:"name"`
+	Key    string   `json:"key"`
+	Scopes []string `json:"scopes"`
+	// MonthlyBudget is the number of requests allowed per calendar month (0 means unlimited)
+	MonthlyBudget int `json:"monthly_budget,omitempty"`
+}
+
+// AuthConfig holds the API keys of the server
+type AuthConfig struct {
+	Keys []APIKey
+}
+
+// LoadAuthConfig loads the API keys from the JSON array in the file named by API_KEYS_FILE.
+// It returns nil if authentication is disabled, i.e. API_KEYS_FILE is not set.
+func LoadAuthConfig() (*AuthConfig, error) {
+	// Try to load default .env file, ignore if not found
+	_ = godotenv.Load()
+
+	path := os.Getenv("API_KEYS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ConfigError{Field: "API_KEYS_FILE", Message: err.Error()}
+	}
+
+	cfg := &AuthConfig{}
+	if err := json.Unmarshal(content, &cfg.Keys); err != nil {
+		return nil, &ConfigError{Field: "API_KEYS_FILE", Message: "invalid JSON: " + err.Error()}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that every key has a unique name and value and known scopes
+func (c *AuthConfig) Validate() error {
+	if len(c.Keys) == 0 {
+		return &ConfigError{Field: "API_KEYS_FILE", Message: "must define at least one key"}
+	}
+	names := make(map[string]bool, len(c.Keys))
+	keys := make(map[string]bool, len(c.Keys))
+	for i, key := range c.Keys {
+		field := "API_KEYS_FILE[" + strconv.Itoa(i) + "]"
+		switch {
+		case key.Name == "" || names[key.Name]:
+			return &ConfigError{Field: field, Message: "name must be set and unique"}
+		case len(key.Key) < 16 || keys[key.Key]:
+			return &ConfigError{Field: field, Message: "key must be unique and at least 16 characters long"}
+		case key.MonthlyBudget < 0:
+			return &ConfigError{Field: field, Message: "monthly_budget must not be negative"}
+		case len(key.Scopes) == 0:
+			return &ConfigError{Field: field, Message: "scopes must not be empty"}
+		}
+		for _, scope := range key.Scopes {
+			if scope != ScopeRatesRead && scope != ScopeAdmin {
+				return &ConfigError{Field: field, Message: "unknown scope " + strconv.Quote(scope) + ", expected rates:read or admin"}
+			}
+		}
+		names[key.Name] = true
+		keys[key.Key] = true
+	}
+	return nil
 }
 
 // Validate checks if the configuration is valid
@@ -71,5 +301,14 @@ func (c *CurrencyAPIConfig) Validate() error {
 	if c.Timeout <= 0 {
 		return errors.New("timeout must be positive")
 	}
-	return nil
+	if c.RateLimitPerMinute < 0 {
+		return errors.New("rate limit must not be negative")
+	}
+	if c.QuotaReserve < 0 {
+		return errors.New("quota reserve must not be negative")
+	}
+	if c.CacheTTL < 0 {
+		return errors.New("cache TTL must not be negative")
+	}
+	return c.validateFallbacks()
 }
@@ -2,13 +2,16 @@ package currency_converter
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/BohdanKyryliuk/golang/config"
 	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/ecb"
+	"github.com/BohdanKyryliuk/golang/currencyapi/openexchangerates"
+	"github.com/BohdanKyryliuk/golang/money"
 )
 
 // Client represents a currency converter client with its dependencies
@@ -22,6 +25,17 @@ type Config struct {
 	APIKey         string
 	Timeout        time.Duration // HTTP client timeout
 	RequestTimeout time.Duration // Individual request timeout (default: 10s)
+	BaseURL        string        // API endpoint override (default: currencyapi.DefaultBaseURL)
+	// RetryPolicy controls retries of temporary API failures (default: currencyapi.DefaultRetryPolicy)
+	RetryPolicy *currencyapi.RetryPolicy
+	// RateLimit limits outgoing API requests (optional)
+	RateLimit *currencyapi.RateLimit
+	// QuotaReserve is the number of monthly requests kept for essential calls (0 disables the guard)
+	QuotaReserve int
+	// Cache enables caching of API responses (optional)
+	Cache *currencyapi.CacheOptions
+	// Fallbacks are providers tried in order when the API fails temporarily or runs out of quota (optional)
+	Fallbacks []currencyapi.Client
 }
 
 // CurrencyConverterError wraps errors from the currency converter
@@ -52,15 +66,21 @@ func New(cfg Config) (*Client, error) {
 		cfg.Timeout = 15 * time.Second
 	}
 
-	// Set default request timeout if not provided
-	if cfg.RequestTimeout == 0 {
-		cfg.RequestTimeout = 10 * time.Second
+	// Retry temporary failures by default
+	if cfg.RetryPolicy == nil {
+		policy := currencyapi.DefaultRetryPolicy()
+		cfg.RetryPolicy = &policy
 	}
 
-	apiClient, err := currencyapi.NewHttpApiClient(
-		cfg.APIKey,
+	opts := []currencyapi.HttpApiClientOption{
 		currencyapi.WithTimeout(cfg.Timeout),
-	)
+		currencyapi.WithRetryPolicy(*cfg.RetryPolicy),
+	}
+	if cfg.BaseURL != "" {
+		opts = append(opts, currencyapi.WithBaseURL(cfg.BaseURL))
+	}
+
+	apiClient, err := currencyapi.NewHttpApiClient(cfg.APIKey, opts...)
 	if err != nil {
 		return nil, &CurrencyConverterError{
 			Operation: "create_client",
@@ -68,6 +88,70 @@ func New(cfg Config) (*Client, error) {
 		}
 	}
 
+	return NewWithAPIClient(cfg, apiClient)
+}
+
+// NewWithAPIClient creates a new Client on top of an existing currencyapi.Client,
+// such as an alternative provider or currencyapitest.FakeClient. The API key and
+// HTTP settings of the config are ignored; rate limiting, quota and cache options apply.
+func NewWithAPIClient(cfg Config, apiClient currencyapi.Client) (*Client, error) {
+	if apiClient == nil {
+		return nil, &CurrencyConverterError{
+			Operation: "init",
+			Err:       errors.New("API client is required"),
+		}
+	}
+
+	// Set default request timeout if not provided
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = 10 * time.Second
+	}
+
+	var err error
+	if cfg.RateLimit != nil {
+		apiClient, err = currencyapi.NewRateLimitedClient(apiClient, *cfg.RateLimit)
+		if err != nil {
+			return nil, &CurrencyConverterError{
+				Operation: "create_rate_limiter",
+				Err:       err,
+			}
+		}
+	}
+
+	if cfg.QuotaReserve > 0 {
+		apiClient, err = currencyapi.NewQuotaGuard(apiClient, currencyapi.QuotaGuardOptions{
+			Reserve: cfg.QuotaReserve,
+		})
+		if err != nil {
+			return nil, &CurrencyConverterError{
+				Operation: "create_quota_guard",
+				Err:       err,
+			}
+		}
+	}
+
+	// Fall back after the quota guard so that a reserved quota switches providers
+	if len(cfg.Fallbacks) > 0 {
+		apiClient, err = currencyapi.NewFailoverClient(apiClient, cfg.Fallbacks...)
+		if err != nil {
+			return nil, &CurrencyConverterError{
+				Operation: "create_failover",
+				Err:       err,
+			}
+		}
+	}
+
+	// Cache outermost so that cache hits don't spend rate limit tokens or quota
+	if cfg.Cache != nil {
+		apiClient, err = currencyapi.NewCachingClient(apiClient, *cfg.Cache)
+		if err != nil {
+			return nil, &CurrencyConverterError{
+				Operation: "create_cache",
+				Err:       err,
+			}
+		}
+	}
+
 	return &Client{
 		config:    cfg,
 		apiClient: apiClient,
@@ -90,14 +174,51 @@ func NewFromEnv() (*Client, error) {
 		}
 	}
 
-	return New(Config{
-		APIKey:  cfg.APIKey,
-		Timeout: cfg.Timeout,
-	})
+	converterCfg := Config{
+		APIKey:       cfg.APIKey,
+		Timeout:      cfg.Timeout,
+		BaseURL:      cfg.BaseURL,
+		QuotaReserve: cfg.QuotaReserve,
+	}
+	if cfg.RateLimitPerMinute > 0 {
+		converterCfg.RateLimit = &currencyapi.RateLimit{
+			Requests: cfg.RateLimitPerMinute,
+			Per:      time.Minute,
+			Burst:    1,
+		}
+	}
+	if cfg.CacheTTL > 0 {
+		converterCfg.Cache = &currencyapi.CacheOptions{
+			LatestTTL: cfg.CacheTTL,
+		}
+	}
+	for _, name := range cfg.Fallbacks {
+		fallback, err := newFallback(name, cfg)
+		if err != nil {
+			return nil, &CurrencyConverterError{
+				Operation: "create_fallback",
+				Err:       err,
+			}
+		}
+		converterCfg.Fallbacks = append(converterCfg.Fallbacks, fallback)
+	}
+
+	return New(converterCfg)
+}
+
+// newFallback creates a fallback provider by its CURRENCY_API_FALLBACKS name
+func newFallback(name string, cfg *config.CurrencyAPIConfig) (currencyapi.Client, error) {
+	switch name {
+	case config.FallbackECB:
+		return ecb.NewClient(), nil
+	case config.FallbackOpenExchangeRates:
+		return openexchangerates.NewClient(cfg.OpenExchangeRatesAppID)
+	}
+	return nil, errors.New("unknown fallback provider " + name)
 }
 
-// CheckStatus returns the API status or an error
-func (c *Client) CheckStatus(ctx context.Context) (string, error) {
+// CheckStatus returns a summary of the API status or an error
+func (c *Client) CheckStatus(ctx context.Context) (*StatusSummary, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), c.config.RequestTimeout)
@@ -106,21 +227,13 @@ func (c *Client) CheckStatus(ctx context.Context) (string, error) {
 
 	status, err := c.apiClient.Status(ctx)
 	if err != nil {
-		return "", c.handleAPIError("check_status", err)
+		return nil, c.handleAPIError("check_status", err)
 	}
-
-	jsonBytes, err := json.Marshal(status)
-	if err != nil {
-		return "", &CurrencyConverterError{
-			Operation: "marshal_status",
-			Err:       err,
-		}
-	}
-	return string(jsonBytes), nil
+	return newStatusSummary(status), nil
 }
 
-// GetCurrencies returns available currencies or an error
-func (c *Client) GetCurrencies(ctx context.Context) (string, error) {
+// GetCurrencies returns the catalog of available currencies or an error
+func (c *Client) GetCurrencies(ctx context.Context) (*CurrencyCatalog, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), c.config.RequestTimeout)
@@ -129,17 +242,9 @@ func (c *Client) GetCurrencies(ctx context.Context) (string, error) {
 
 	currencies, err := c.apiClient.Currencies(ctx, nil)
 	if err != nil {
-		return "", c.handleAPIError("get_currencies", err)
-	}
-
-	jsonBytes, err := json.Marshal(currencies)
-	if err != nil {
-		return "", &CurrencyConverterError{
-			Operation: "marshal_currencies",
-			Err:       err,
-		}
+		return nil, c.handleAPIError("get_currencies", err)
 	}
-	return string(jsonBytes), nil
+	return &CurrencyCatalog{Currencies: currencies.Data}, nil
 }
 
 // LatestRatesParams holds parameters for fetching latest rates
@@ -148,8 +253,14 @@ type LatestRatesParams struct {
 	Currencies   []string // Target currency codes to fetch
 }
 
-// GetLatestRates returns the latest exchange rates or an error
-func (c *Client) GetLatestRates(ctx context.Context, params *LatestRatesParams) (string, error) {
+// GetLatestRates returns a table of the latest exchange rates or an error
+func (c *Client) GetLatestRates(ctx context.Context, params *LatestRatesParams) (*RateTable, error) {
+	if params != nil {
+		if err := validateRatesParams(params.BaseCurrency, params.Currencies); err != nil {
+			return nil, &CurrencyConverterError{Operation: "get_latest_rates", Err: err}
+		}
+	}
+
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), c.config.RequestTimeout)
@@ -168,17 +279,154 @@ func (c *Client) GetLatestRates(ctx context.Context, params *LatestRatesParams)
 
 	latestRates, err := c.apiClient.Latest(ctx, apiParams)
 	if err != nil {
-		return "", c.handleAPIError("get_latest_rates", err)
+		return nil, c.handleAPIError("get_latest_rates", err)
+	}
+	return newRateTable(apiParams.BaseCurrency, "", latestRates.Data, latestRates.Meta), nil
+}
+
+// HistoricalRatesParams holds parameters for fetching historical rates
+type HistoricalRatesParams struct {
+	Date         string   // Required: date in format YYYY-MM-DD
+	BaseCurrency string   // Base currency code (default: USD)
+	Currencies   []string // Target currency codes to fetch
+}
+
+// GetHistoricalRates returns a table of the exchange rates of a past date or an error
+func (c *Client) GetHistoricalRates(ctx context.Context, params *HistoricalRatesParams) (*RateTable, error) {
+	if params == nil {
+		params = &HistoricalRatesParams{}
+	}
+	if err := validateDate(params.Date, true); err != nil {
+		return nil, &CurrencyConverterError{Operation: "get_historical_rates", Err: err}
+	}
+	if err := validateRatesParams(params.BaseCurrency, params.Currencies); err != nil {
+		return nil, &CurrencyConverterError{Operation: "get_historical_rates", Err: err}
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.config.RequestTimeout)
+		defer cancel()
+	}
+
+	apiParams := &currencyapi.HistoricalParams{
+		Date:         params.Date,
+		BaseCurrency: params.BaseCurrency,
+		Currencies:   params.Currencies,
+	}
+	if apiParams.BaseCurrency == "" {
+		apiParams.BaseCurrency = "USD"
+	}
+
+	historicalRates, err := c.apiClient.Historical(ctx, apiParams)
+	if err != nil {
+		return nil, c.handleAPIError("get_historical_rates", err)
 	}
+	return newRateTable(apiParams.BaseCurrency, params.Date, historicalRates.Data, historicalRates.Meta), nil
+}
+
+// ConvertParams holds parameters for converting an amount
+type ConvertParams struct {
+	From   string       // Required: currency code of the amount
+	To     []string     // Required: target currency codes
+	Amount money.Amount // Required: positive amount to convert
+	Date   string       // Optional: date for a historical conversion (YYYY-MM-DD)
+}
 
-	jsonBytes, err := json.Marshal(latestRates)
+// Convert converts an amount to one or more currencies or returns an error.
+// Results are exact; round them with currencyapi.CurrencyInfo.Round for display.
+func (c *Client) Convert(ctx context.Context, params *ConvertParams) (*Conversion, error) {
+	if err := validateConvertParams(params); err != nil {
+		return nil, &CurrencyConverterError{Operation: "convert", Err: err}
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.config.RequestTimeout)
+		defer cancel()
+	}
+
+	converted, err := c.apiClient.Convert(ctx, &currencyapi.ConvertParams{
+		BaseCurrency: params.From,
+		Currencies:   params.To,
+		Value:        params.Amount,
+		Date:         params.Date,
+	})
 	if err != nil {
-		return "", &CurrencyConverterError{
-			Operation: "marshal_latest_rates",
-			Err:       err,
+		return nil, c.handleAPIError("convert", err)
+	}
+	return newConversion(params, converted), nil
+}
+
+// validateConvertParams checks the parameters of a conversion
+func validateConvertParams(params *ConvertParams) error {
+	if params == nil {
+		return &currencyapi.ValidationError{Field: "params", Message: "convert parameters are required"}
+	}
+	if params.From == "" {
+		return &currencyapi.ValidationError{Field: "from", Message: "source currency is required"}
+	}
+	if len(params.To) == 0 {
+		return &currencyapi.ValidationError{Field: "to", Message: "at least one target currency is required"}
+	}
+	if err := validateCurrency("from", params.From); err != nil {
+		return err
+	}
+	if err := validateCurrencies("to", params.To); err != nil {
+		return err
+	}
+	if params.Amount.Sign() <= 0 {
+		return &currencyapi.ValidationError{Field: "amount", Message: "amount must be positive"}
+	}
+	return validateDate(params.Date, false)
+}
+
+// validateRatesParams checks the currency codes of a rate table request
+func validateRatesParams(base string, currencies []string) error {
+	if err := validateCurrency("base", base); err != nil {
+		return err
+	}
+	return validateCurrencies("currencies", currencies)
+}
+
+// validateCurrencies checks that every code of a list is a currency code
+func validateCurrencies(field string, codes []string) error {
+	for _, code := range codes {
+		if err := validateCurrency(field, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCurrency checks that a code, if set, is made of three uppercase letters
+func validateCurrency(field, code string) error {
+	if code == "" {
+		return nil
+	}
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return &currencyapi.ValidationError{Field: field, Message: "currency codes must be three letters, e.g. USD"}
+	}
+	return nil
+}
+
+// validateDate checks that a date is in format YYYY-MM-DD and not in the future
+func validateDate(date string, required bool) error {
+	if date == "" {
+		if required {
+			return &currencyapi.ValidationError{Field: "date", Message: "date is required"}
 		}
+		return nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return &currencyapi.ValidationError{Field: "date", Message: "date must be in format YYYY-MM-DD"}
+	}
+	if parsed.After(time.Now().UTC()) {
+		return &currencyapi.ValidationError{Field: "date", Message: "date must not be in the future"}
 	}
-	return string(jsonBytes), nil
+	return nil
 }
 
 // handleAPIError processes API errors and wraps them appropriately
@@ -0,0 +1,96 @@
+package currency_converter
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+func newTestClient(t *testing.T) (*Client, *currencyapitest.FakeClient) {
+	t.Helper()
+	fake := currencyapitest.NewFakeClient("USD", map[string]float64{"EUR": 0.8, "UAH": 40})
+	client, err := NewWithAPIClient(Config{}, fake)
+	if err != nil {
+		t.Fatalf("NewWithAPIClient() error = %v", err)
+	}
+	return client, fake
+}
+
+func TestCheckStatus(t *testing.T) {
+	client, fake := newTestClient(t)
+	fake.SetQuota(300, 120)
+
+	status, err := client.CheckStatus(context.Background())
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if status.Month != (QuotaUsage{Total: 300, Used: 120, Remaining: 180}) {
+		t.Errorf("Unexpected monthly quota: %+v", status.Month)
+	}
+}
+
+func TestGetCurrencies(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	catalog, err := client.GetCurrencies(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrencies() error = %v", err)
+	}
+	if codes := catalog.Codes(); !slices.Equal(codes, []string{"EUR", "UAH", "USD"}) {
+		t.Errorf("Codes() = %v", codes)
+	}
+	if info, ok := catalog.Get("UAH"); !ok || info.DecimalDigits != 2 {
+		t.Errorf("Get(UAH) = %+v, %v", info, ok)
+	}
+	if _, ok := catalog.Get("XYZ"); ok {
+		t.Error("Get(XYZ) found an unknown currency")
+	}
+}
+
+func TestGetLatestRates(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	table, err := client.GetLatestRates(context.Background(), &LatestRatesParams{
+		BaseCurrency: "EUR",
+		Currencies:   []string{"UAH"},
+	})
+	if err != nil {
+		t.Fatalf("GetLatestRates() error = %v", err)
+	}
+	if table.BaseCurrency != "EUR" || table.Provider != currencyapitest.ProviderName || table.LastUpdatedAt == "" {
+		t.Errorf("Unexpected rate table: %+v", table)
+	}
+	if rate, ok := table.Rate("UAH"); !ok || rate.String() != "50" {
+		t.Errorf("Rate(UAH) = %s, %v", rate, ok)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	client, fake := newTestClient(t)
+
+	conversion, err := client.Convert(context.Background(), &ConvertParams{
+		From:   "USD",
+		To:     []string{"EUR"},
+		Amount: money.MustParse("12.50"),
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if conversion.Amount.String() != "12.50 USD" || conversion.Results["EUR"].String() != "10.000 EUR" {
+		t.Errorf("Unexpected conversion: %+v", conversion)
+	}
+
+	// Invalid parameters are rejected before calling the API
+	fake.ResetCalls()
+	_, err = client.Convert(context.Background(), &ConvertParams{From: "USD", To: []string{"EUR"}})
+	if !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	if n := fake.CallCount(currencyapitest.EndpointConvert); n != 0 {
+		t.Errorf("Expected no API calls, got %d", n)
+	}
+}
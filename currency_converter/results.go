@@ -0,0 +1,106 @@
+package currency_converter
+
+import (
+	"slices"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// QuotaUsage describes how much of a request quota has been used
+type QuotaUsage struct {
+	Total     int `json:"total"`
+	Used      int `json:"used"`
+	Remaining int `json:"remaining"`
+}
+
+// StatusSummary describes the API account and its request quotas
+type StatusSummary struct {
+	AccountID int64      `json:"account_id"`
+	Month     QuotaUsage `json:"month"`
+	Grace     QuotaUsage `json:"grace"`
+}
+
+// newStatusSummary converts an API status response
+func newStatusSummary(status *currencyapi.StatusResponse) *StatusSummary {
+	return &StatusSummary{
+		AccountID: status.AccountID,
+		Month:     QuotaUsage(status.Quotas.Month),
+		Grace:     QuotaUsage(status.Quotas.Grace),
+	}
+}
+
+// CurrencyCatalog holds the currencies supported by the API keyed by currency code
+type CurrencyCatalog struct {
+	Currencies map[string]currencyapi.CurrencyInfo `json:"currencies"`
+}
+
+// Get returns the information about a currency
+func (c *CurrencyCatalog) Get(code string) (currencyapi.CurrencyInfo, bool) {
+	info, ok := c.Currencies[code]
+	return info, ok
+}
+
+// Codes returns the sorted currency codes of the catalog
+func (c *CurrencyCatalog) Codes() []string {
+	codes := make([]string, 0, len(c.Currencies))
+	for code := range c.Currencies {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// RateTable holds the value of one unit of the base currency in other currencies
+type RateTable struct {
+	BaseCurrency  string                  `json:"base_currency"`
+	Date          string                  `json:"date,omitempty"` // Set for historical rates
+	Rates         map[string]money.Amount `json:"rates"`
+	LastUpdatedAt string                  `json:"last_updated_at"`
+	Provider      string                  `json:"provider,omitempty"`
+}
+
+// newRateTable converts API rates to a rate table
+func newRateTable(base, date string, data map[string]currencyapi.RateInfo, meta currencyapi.ResponseMeta) *RateTable {
+	table := &RateTable{
+		BaseCurrency:  base,
+		Date:          date,
+		Rates:         make(map[string]money.Amount, len(data)),
+		LastUpdatedAt: meta.LastUpdatedAt,
+		Provider:      meta.Provider,
+	}
+	for code, rate := range data {
+		table.Rates[code] = rate.Value
+	}
+	return table
+}
+
+// Rate returns the value of one unit of the base currency in the currency
+func (t *RateTable) Rate(code string) (money.Amount, bool) {
+	rate, ok := t.Rates[code]
+	return rate, ok
+}
+
+// Conversion is the result of converting an amount to other currencies
+type Conversion struct {
+	Amount        money.Money            `json:"amount"`
+	Results       map[string]money.Money `json:"results"`
+	Date          string                 `json:"date,omitempty"` // Set for historical conversions
+	LastUpdatedAt string                 `json:"last_updated_at"`
+	Provider      string                 `json:"provider,omitempty"`
+}
+
+// newConversion converts an API convert response
+func newConversion(params *ConvertParams, response *currencyapi.ConvertResponse) *Conversion {
+	conversion := &Conversion{
+		Amount:        money.NewMoney(params.Amount, params.From),
+		Results:       make(map[string]money.Money, len(response.Data)),
+		Date:          params.Date,
+		LastUpdatedAt: response.Meta.LastUpdatedAt,
+		Provider:      response.Meta.Provider,
+	}
+	for code, result := range response.Data {
+		conversion.Results[code] = money.NewMoney(result.Value, code)
+	}
+	return conversion
+}
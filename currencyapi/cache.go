@@ -0,0 +1,296 @@
+package currencyapi
+
+import (
+	"container/list"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultCurrenciesTTL is how long the currency list is cached by default
+	DefaultCurrenciesTTL = 12 * time.Hour
+	// DefaultLatestTTL is how long latest rates are cached by default
+	DefaultLatestTTL = time.Minute
+	// DefaultSharedCallTimeout is the default timeout of upstream calls shared by concurrent requests
+	DefaultSharedCallTimeout = 30 * time.Second
+	// DefaultMaxHistorical is the default number of historical responses kept in the cache
+	DefaultMaxHistorical = 1000
+)
+
+// CacheOptions configures a CachingClient
+type CacheOptions struct {
+	CurrenciesTTL time.Duration // How long the currency list is cached (default: 12 hours)
+	LatestTTL     time.Duration // How long latest rates are cached per base/currency set (default: 1 minute)
+	// SharedCallTimeout bounds upstream calls shared by concurrent requests, which don't
+	// end with the context of the request that started them (default: 30 seconds)
+	SharedCallTimeout time.Duration
+	// MaxHistorical is the number of historical responses kept; the least recently used
+	// one is evicted first (default: 1000)
+	MaxHistorical int
+}
+
+// cacheEntry is a cached response with its expiration time
+type cacheEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// CachingClient is a Client decorator that caches responses of the inner client.
+// Historical rates never change and are kept until evicted by more recently used
+// ones, so that the cache stays bounded whatever dates callers ask for. Concurrent identical
+// requests share a single upstream call. Essential requests, like the refreshes of
+// the workers, always reach the upstream for latest rates and currencies and refresh
+// the cache, and never share calls, so that they don't spend the quota reserved for
+// them on behalf of other requests or fail with their errors. Status and Convert are
+// not cached.
+type CachingClient struct {
+	inner      Client
+	opts       CacheOptions
+	now        func() time.Time
+	group      singleflight.Group
+	currencies map[string]cacheEntry[*CurrenciesResponse]
+	latest     map[string]cacheEntry[*LatestResponse]
+	historical *lru[*HistoricalResponse]
+	mu         sync.RWMutex
+}
+
+// NewCachingClient wraps a client with an in-memory response cache
+func NewCachingClient(inner Client, opts CacheOptions) (Client, error) {
+	if inner == nil {
+		return nil, &ValidationError{Field: "inner", Message: "client is required"}
+	}
+	if opts.CurrenciesTTL <= 0 {
+		opts.CurrenciesTTL = DefaultCurrenciesTTL
+	}
+	if opts.LatestTTL <= 0 {
+		opts.LatestTTL = DefaultLatestTTL
+	}
+	if opts.SharedCallTimeout <= 0 {
+		opts.SharedCallTimeout = DefaultSharedCallTimeout
+	}
+	if opts.MaxHistorical <= 0 {
+		opts.MaxHistorical = DefaultMaxHistorical
+	}
+
+	return &CachingClient{
+		inner:      inner,
+		opts:       opts,
+		now:        time.Now,
+		currencies: make(map[string]cacheEntry[*CurrenciesResponse]),
+		latest:     make(map[string]cacheEntry[*LatestResponse]),
+		historical: newLRU[*HistoricalResponse](opts.MaxHistorical),
+	}, nil
+}
+
+// cacheKey builds a cache key from the endpoint and its parameters.
+// The currency list is sorted so that the order of codes doesn't matter.
+func cacheKey(endpoint string, parts []string, currencies []string) string {
+	codes := slices.Clone(currencies)
+	slices.Sort(codes)
+	return endpoint + "|" + strings.Join(parts, "|") + "|" + strings.Join(codes, ",")
+}
+
+// share runs fetch once for concurrent non-essential calls with the same key. The
+// shared call runs without the cancellation and deadline of the caller that started
+// it, and every caller stops waiting when its own context is done.
+func (c *CachingClient) share(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	if IsEssential(ctx) {
+		return fetch(ctx)
+	}
+
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		sharedCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.SharedCallTimeout)
+		defer cancel()
+		return fetch(sharedCtx)
+	})
+	select {
+	case r := <-result:
+		return r.Val, r.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Status returns the current API status
+func (c *CachingClient) Status(ctx context.Context) (*StatusResponse, error) {
+	return c.inner.Status(ctx)
+}
+
+// Currencies returns available currencies
+func (c *CachingClient) Currencies(ctx context.Context, params *CurrenciesParams) (*CurrenciesResponse, error) {
+	var key string
+	if params != nil {
+		key = cacheKey("currencies", []string{params.Type}, params.Currencies)
+	} else {
+		key = cacheKey("currencies", nil, nil)
+	}
+
+	c.mu.RLock()
+	entry, ok := c.currencies[key]
+	c.mu.RUnlock()
+	if ok && !IsEssential(ctx) && c.now().Before(entry.expiresAt) {
+		return cloneCurrencies(entry.value), nil
+	}
+
+	value, err := c.share(ctx, key, func(ctx context.Context) (interface{}, error) {
+		response, err := c.inner.Currencies(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.currencies[key] = cacheEntry[*CurrenciesResponse]{value: response, expiresAt: c.now().Add(c.opts.CurrenciesTTL)}
+		c.mu.Unlock()
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneCurrencies(value.(*CurrenciesResponse)), nil
+}
+
+// Latest returns the latest exchange rates
+func (c *CachingClient) Latest(ctx context.Context, params *LatestParams) (*LatestResponse, error) {
+	var key string
+	if params != nil {
+		key = cacheKey("latest", []string{params.BaseCurrency}, params.Currencies)
+	} else {
+		key = cacheKey("latest", nil, nil)
+	}
+
+	c.mu.RLock()
+	entry, ok := c.latest[key]
+	c.mu.RUnlock()
+	if ok && !IsEssential(ctx) && c.now().Before(entry.expiresAt) {
+		return cloneLatest(entry.value), nil
+	}
+
+	value, err := c.share(ctx, key, func(ctx context.Context) (interface{}, error) {
+		response, err := c.inner.Latest(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.latest[key] = cacheEntry[*LatestResponse]{value: response, expiresAt: c.now().Add(c.opts.LatestTTL)}
+		c.mu.Unlock()
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneLatest(value.(*LatestResponse)), nil
+}
+
+// Historical returns historical exchange rates for a specific date
+func (c *CachingClient) Historical(ctx context.Context, params *HistoricalParams) (*HistoricalResponse, error) {
+	if params == nil || params.Date == "" {
+		// Let the inner client report the validation error
+		return c.inner.Historical(ctx, params)
+	}
+
+	key := cacheKey("historical", []string{params.Date, params.BaseCurrency}, params.Currencies)
+
+	// Lookups update the recency of the entry, so they need the write lock
+	c.mu.Lock()
+	cached, ok := c.historical.get(key)
+	c.mu.Unlock()
+	if ok {
+		return cloneHistorical(cached), nil
+	}
+
+	value, err := c.share(ctx, key, func(ctx context.Context) (interface{}, error) {
+		response, err := c.inner.Historical(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.historical.add(key, response)
+		c.mu.Unlock()
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneHistorical(value.(*HistoricalResponse)), nil
+}
+
+// Convert converts an amount from one currency to another
+func (c *CachingClient) Convert(ctx context.Context, params *ConvertParams) (*ConvertResponse, error) {
+	return c.inner.Convert(ctx, params)
+}
+
+// lru holds a bounded number of entries and evicts the least recently used one
+type lru[T any] struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // Of *lruEntry[T], most recently used first
+}
+
+type lruEntry[T any] struct {
+	key   string
+	value T
+}
+
+func newLRU[T any](capacity int) *lru[T] {
+	return &lru[T]{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// get returns the value of key and marks it as the most recently used
+func (l *lru[T]) get(key string) (T, bool) {
+	element, ok := l.entries[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruEntry[T]).value, true
+}
+
+// add sets the value of key, evicting the least recently used entry when full
+func (l *lru[T]) add(key string, value T) {
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*lruEntry[T]).value = value
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry[T]{key: key, value: value})
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry[T]).key)
+	}
+}
+
+// len returns the number of entries
+func (l *lru[T]) len() int {
+	return l.order.Len()
+}
+
+// cloneCurrencies copies a cached response so callers can't modify the cache
+func cloneCurrencies(response *CurrenciesResponse) *CurrenciesResponse {
+	clone := *response
+	clone.Data = maps.Clone(response.Data)
+	return &clone
+}
+
+// cloneLatest copies a cached response so callers can't modify the cache
+func cloneLatest(response *LatestResponse) *LatestResponse {
+	clone := *response
+	clone.Data = maps.Clone(response.Data)
+	return &clone
+}
+
+// cloneHistorical copies a cached response so callers can't modify the cache
+func cloneHistorical(response *HistoricalResponse) *HistoricalResponse {
+	clone := *response
+	clone.Data = maps.Clone(response.Data)
+	return &clone
+}
@@ -0,0 +1,342 @@
+package currencyapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+func newTestCachingClient(t *testing.T, stub *stubClient) (*CachingClient, *time.Time) {
+	t.Helper()
+
+	client, err := NewCachingClient(stub, CacheOptions{LatestTTL: time.Minute, CurrenciesTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewCachingClient() error = %v", err)
+	}
+
+	now := time.Date(2025, 12, 5, 12, 0, 0, 0, time.UTC)
+	caching := client.(*CachingClient)
+	caching.now = func() time.Time { return now }
+	return caching, &now
+}
+
+func TestCachingClientLatest(t *testing.T) {
+	stub := newStubClient()
+	stub.latest.Data = map[string]RateInfo{"EUR": {Code: "EUR", Value: money.MustParse("0.85")}}
+	client, now := newTestCachingClient(t, stub)
+
+	ctx := context.Background()
+	params := &LatestParams{BaseCurrency: "USD", Currencies: []string{"EUR", "GBP"}}
+
+	first, err := client.Latest(ctx, params)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+
+	// Modifying a response must not affect the cache
+	delete(first.Data, "EUR")
+
+	// The currency order doesn't matter for the cache key
+	second, err := client.Latest(ctx, &LatestParams{BaseCurrency: "USD", Currencies: []string{"GBP", "EUR"}})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if _, ok := second.Data["EUR"]; !ok {
+		t.Error("Cached response was modified by the caller")
+	}
+	if got := stub.count("latest"); got != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", got)
+	}
+
+	// A different base currency is cached separately
+	if _, err := client.Latest(ctx, &LatestParams{BaseCurrency: "EUR"}); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if got := stub.count("latest"); got != 2 {
+		t.Errorf("Expected 2 upstream calls, got %d", got)
+	}
+
+	// Entries expire after the TTL
+	*now = now.Add(time.Minute)
+	if _, err := client.Latest(ctx, params); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if got := stub.count("latest"); got != 3 {
+		t.Errorf("Expected 3 upstream calls after expiry, got %d", got)
+	}
+}
+
+func TestCachingClientHistoricalNeverExpires(t *testing.T) {
+	stub := newStubClient()
+	client, now := newTestCachingClient(t, stub)
+
+	ctx := context.Background()
+	params := &HistoricalParams{Date: "2025-01-01", BaseCurrency: "USD"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Historical(ctx, params); err != nil {
+			t.Fatalf("Historical() error = %v", err)
+		}
+		*now = now.Add(365 * 24 * time.Hour)
+	}
+	if got := stub.count("historical"); got != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", got)
+	}
+
+	if _, err := client.Historical(ctx, &HistoricalParams{Date: "2025-01-02", BaseCurrency: "USD"}); err != nil {
+		t.Fatalf("Historical() error = %v", err)
+	}
+	if got := stub.count("historical"); got != 2 {
+		t.Errorf("Expected 2 upstream calls, got %d", got)
+	}
+}
+
+func TestCachingClientCurrencies(t *testing.T) {
+	stub := newStubClient()
+	client, now := newTestCachingClient(t, stub)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Currencies(ctx, nil); err != nil {
+			t.Fatalf("Currencies() error = %v", err)
+		}
+	}
+	if got := stub.count("currencies"); got != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", got)
+	}
+
+	*now = now.Add(time.Hour)
+	if _, err := client.Currencies(ctx, nil); err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+	if got := stub.count("currencies"); got != 2 {
+		t.Errorf("Expected 2 upstream calls after expiry, got %d", got)
+	}
+}
+
+func TestCachingClientDoesNotCacheErrors(t *testing.T) {
+	stub := newStubClient()
+	stub.err = &HTTPError{StatusCode: 503}
+	client, _ := newTestCachingClient(t, stub)
+
+	ctx := context.Background()
+	if _, err := client.Latest(ctx, nil); !IsHTTPError(err) {
+		t.Fatalf("Expected HTTPError, got %v", err)
+	}
+
+	stub.mu.Lock()
+	stub.err = nil
+	stub.mu.Unlock()
+
+	if _, err := client.Latest(ctx, nil); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if got := stub.count("latest"); got != 2 {
+		t.Errorf("Expected 2 upstream calls, got %d", got)
+	}
+}
+
+func TestCachingClientSharesConcurrentRequests(t *testing.T) {
+	stub := newStubClient()
+	release := make(chan struct{})
+	stub.onRequest = func(endpoint string) { <-release }
+	client, _ := newTestCachingClient(t, stub)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Latest(context.Background(), &LatestParams{BaseCurrency: "USD"})
+			errs <- err
+		}()
+	}
+
+	// Give every caller a chance to join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+	}
+	if got := stub.count("latest"); got != 1 {
+		t.Errorf("Expected 1 shared upstream call, got %d", got)
+	}
+}
+
+func TestCachingClientSharedCallOutlivesCaller(t *testing.T) {
+	stub := newStubClient()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	stub.onRequest = func(endpoint string) {
+		started <- struct{}{}
+		<-release
+	}
+	client, _ := newTestCachingClient(t, stub)
+	params := &LatestParams{BaseCurrency: "USD"}
+
+	// The first caller starts the shared call and gives up while it's in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.Latest(ctx, params)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := client.Latest(context.Background(), params)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the canceled caller to get context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Expected the other caller to get the shared response, got %v", err)
+	}
+	if got := stub.count("latest"); got != 1 {
+		t.Errorf("Expected 1 shared upstream call, got %d", got)
+	}
+}
+
+func TestCachingClientEssentialCallsAreNotShared(t *testing.T) {
+	stub := newStubClient()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	stub.onRequest = func(endpoint string) {
+		started <- struct{}{}
+		<-release
+	}
+	client, _ := newTestCachingClient(t, stub)
+	params := &LatestParams{BaseCurrency: "USD"}
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := client.Latest(context.Background(), params)
+		errs <- err
+	}()
+	<-started
+
+	// The essential call makes its own upstream call instead of joining the other one
+	go func() {
+		_, err := client.Latest(WithEssential(context.Background()), params)
+		errs <- err
+	}()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Essential call joined the shared upstream call")
+	}
+
+	close(release)
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+	}
+	if got := stub.count("latest"); got != 2 {
+		t.Errorf("Expected 2 upstream calls, got %d", got)
+	}
+}
+
+func TestCachingClientEssentialCallsRefresh(t *testing.T) {
+	stub := newStubClient()
+	client, _ := newTestCachingClient(t, stub)
+
+	ctx := context.Background()
+	params := &LatestParams{BaseCurrency: "USD"}
+	if _, err := client.Latest(ctx, params); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+
+	// Essential calls skip the cached entry and refresh it for the other callers
+	for range 2 {
+		if _, err := client.Latest(WithEssential(ctx), params); err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+	}
+	if _, err := client.Latest(ctx, params); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if got := stub.count("latest"); got != 3 {
+		t.Errorf("Expected 3 upstream calls, got %d", got)
+	}
+}
+
+func TestCachingClientEssentialCallsBypassCache(t *testing.T) {
+	stub := newStubClient()
+	client, _ := newTestCachingClient(t, stub)
+
+	ctx := context.Background()
+	if _, err := client.Currencies(ctx, nil); err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+
+	// Every essential call reaches the upstream client although the response is cached
+	for i := range 2 {
+		if _, err := client.Currencies(WithEssential(ctx), nil); err != nil {
+			t.Fatalf("Currencies() error = %v", err)
+		}
+		if got := stub.count("currencies"); got != i+2 {
+			t.Errorf("Essential call %d didn't reach the upstream client: %d calls", i+1, got)
+		}
+	}
+	if _, err := client.Currencies(ctx, nil); err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+	if got := stub.count("currencies"); got != 3 {
+		t.Errorf("Expected 3 upstream calls, got %d", got)
+	}
+}
+
+func TestCachingClientHistoricalIsBounded(t *testing.T) {
+	stub := newStubClient()
+	client, err := NewCachingClient(stub, CacheOptions{MaxHistorical: 2})
+	if err != nil {
+		t.Fatalf("NewCachingClient() error = %v", err)
+	}
+	caching := client.(*CachingClient)
+
+	ctx := context.Background()
+	historical := func(date string) {
+		t.Helper()
+		if _, err := client.Historical(ctx, &HistoricalParams{Date: date, BaseCurrency: "USD"}); err != nil {
+			t.Fatalf("Historical() error = %v", err)
+		}
+	}
+
+	historical("2025-01-01")
+	historical("2025-01-02")
+	historical("2025-01-01") // Cached, and now more recently used than 2025-01-02
+	historical("2025-01-03") // Evicts 2025-01-02
+	if got := stub.count("historical"); got != 3 {
+		t.Errorf("Expected 3 upstream calls, got %d", got)
+	}
+	if got := caching.historical.len(); got != 2 {
+		t.Errorf("Expected 2 cached responses, got %d", got)
+	}
+
+	historical("2025-01-01")
+	if got := stub.count("historical"); got != 3 {
+		t.Errorf("Expected the recently used date to stay cached, got %d upstream calls", got)
+	}
+	historical("2025-01-02")
+	if got := stub.count("historical"); got != 4 {
+		t.Errorf("Expected the evicted date to be fetched again, got %d upstream calls", got)
+	}
+}
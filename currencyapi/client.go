@@ -6,7 +6,6 @@ package currencyapi
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -19,6 +18,8 @@ const (
 	DefaultBaseURL = "https://api.currencyapi.com/v3/"
 	// DefaultTimeout is the default HTTP client timeout
 	DefaultTimeout = 10 * time.Second
+	// ProviderName identifies CurrencyAPI in ResponseMeta.Provider
+	ProviderName = "currencyapi"
 )
 
 // Client is a generic interface for currency API clients
@@ -32,9 +33,10 @@ type Client interface {
 
 // HttpApiClient represents an HTTP-based CurrencyAPI client with configurable options
 type HttpApiClient struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
+	apiKey      string
+	baseURL     string
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
 }
 
 // HttpApiClientOption is a function that configures an HttpApiClient
@@ -82,8 +84,38 @@ func NewHttpApiClient(apiKey string, opts ...HttpApiClientOption) (Client, error
 	return c, nil
 }
 
-// doRequest performs an HTTP request and returns the response body or an error
+// doRequest performs an HTTP request and returns the response body or an error.
+// Temporary failures are retried according to the configured retry policy.
 func (c *HttpApiClient) doRequest(ctx context.Context, endpoint string, params map[string]string) ([]byte, error) {
+	body, err := c.doAttempt(ctx, endpoint, params)
+	if err == nil || c.retryPolicy == nil {
+		return body, err
+	}
+
+	for attempt := 2; attempt <= c.retryPolicy.MaxAttempts; attempt++ {
+		if !c.retryPolicy.shouldRetry(err) {
+			return nil, err
+		}
+
+		delay, ok := c.retryPolicy.nextDelay(ctx, attempt-1, err)
+		if !ok {
+			return nil, err
+		}
+		if waitErr := wait(ctx, delay); waitErr != nil {
+			return nil, err
+		}
+
+		body, err = c.doAttempt(ctx, endpoint, params)
+		if err == nil {
+			return body, nil
+		}
+	}
+
+	return nil, err
+}
+
+// doAttempt performs a single HTTP request and returns the response body or an error
+func (c *HttpApiClient) doAttempt(ctx context.Context, endpoint string, params map[string]string) ([]byte, error) {
 	// Build URL with query parameters
 	reqURL, err := url.Parse(c.baseURL + endpoint)
 	if err != nil {
@@ -133,20 +165,21 @@ func (c *HttpApiClient) doRequest(ctx context.Context, endpoint string, params m
 
 	// Check for API errors based on status code
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, c.parseAPIError(resp.StatusCode, body)
+		return nil, c.parseAPIError(resp.StatusCode, body, parseRetryAfter(resp.Header.Get("Retry-After")))
 	}
 
 	return body, nil
 }
 
 // parseAPIError attempts to parse an API error response
-func (c *HttpApiClient) parseAPIError(statusCode int, body []byte) error {
+func (c *HttpApiClient) parseAPIError(statusCode int, body []byte, retryAfter time.Duration) error {
 	var apiErr APIErrorResponse
 	if err := json.Unmarshal(body, &apiErr); err != nil {
 		// If we can't parse the error, return a generic HTTP error
 		return &HTTPError{
 			StatusCode: statusCode,
 			Body:       string(body),
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -155,6 +188,7 @@ func (c *HttpApiClient) parseAPIError(statusCode int, body []byte) error {
 		Code:       apiErr.Error.Code,
 		Message:    apiErr.Error.Message,
 		Info:       apiErr.Error.Info,
+		RetryAfter: retryAfter,
 	}
 }
 
@@ -228,6 +262,7 @@ func (c *HttpApiClient) Latest(ctx context.Context, params *LatestParams) (*Late
 			Err:      err,
 		}
 	}
+	response.Meta.Provider = ProviderName
 
 	return &response, nil
 }
@@ -263,6 +298,7 @@ func (c *HttpApiClient) Historical(ctx context.Context, params *HistoricalParams
 			Err:      err,
 		}
 	}
+	response.Meta.Provider = ProviderName
 
 	return &response, nil
 }
@@ -283,8 +319,8 @@ func (c *HttpApiClient) Convert(ctx context.Context, params *ConvertParams) (*Co
 	if len(params.Currencies) > 0 {
 		queryParams["currencies"] = strings.Join(params.Currencies, ",")
 	}
-	if params.Value != 0 {
-		queryParams["value"] = fmt.Sprintf("%f", params.Value)
+	if !params.Value.IsZero() {
+		queryParams["value"] = params.Value.String()
 	}
 	if params.Date != "" {
 		queryParams["date"] = params.Date
@@ -302,6 +338,7 @@ func (c *HttpApiClient) Convert(ctx context.Context, params *ConvertParams) (*Co
 			Err:      err,
 		}
 	}
+	response.Meta.Provider = ProviderName
 
 	return &response, nil
 }
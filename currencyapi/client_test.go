@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
 )
 
 func TestNewClient(t *testing.T) {
@@ -68,14 +70,12 @@ func TestClient_Latest(t *testing.T) {
 
 		// Return mock response
 		response := LatestResponse{
-			Meta: struct {
-				LastUpdatedAt string `json:"last_updated_at"`
-			}{
+			Meta: ResponseMeta{
 				LastUpdatedAt: "2025-12-05T12:00:00Z",
 			},
 			Data: map[string]RateInfo{
-				"EUR": {Code: "EUR", Value: 0.85},
-				"UAH": {Code: "UAH", Value: 41.5},
+				"EUR": {Code: "EUR", Value: money.MustParse("0.85")},
+				"UAH": {Code: "UAH", Value: money.MustParse("41.5")},
 			},
 		}
 		json.NewEncoder(w).Encode(response)
@@ -101,8 +101,42 @@ func TestClient_Latest(t *testing.T) {
 		t.Errorf("Expected 2 currencies, got %d", len(response.Data))
 	}
 
-	if response.Data["EUR"].Value != 0.85 {
-		t.Errorf("Expected EUR value 0.85, got %f", response.Data["EUR"].Value)
+	if response.Data["EUR"].Value.String() != "0.85" {
+		t.Errorf("Expected EUR value 0.85, got %s", response.Data["EUR"].Value)
+	}
+}
+
+func TestClient_Convert(t *testing.T) {
+	var value string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value = r.URL.Query().Get("value")
+		w.Write([]byte(`{"meta":{"last_updated_at":"2025-12-05T12:00:00Z"},"data":{"EUR":{"code":"EUR","value":1049.3764012345678901}}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewHttpApiClient("test-api-key", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	response, err := client.Convert(context.Background(), &ConvertParams{
+		BaseCurrency: "USD",
+		Currencies:   []string{"EUR"},
+		Value:        money.MustParse("1234.5600000001"),
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	// Amounts are sent and decoded without losing precision
+	if value != "1234.5600000001" {
+		t.Errorf("Expected exact value parameter, got %q", value)
+	}
+	if got := response.Data["EUR"].Value.String(); got != "1049.3764012345678901" {
+		t.Errorf("Expected exact converted value, got %s", got)
+	}
+	if response.Meta.Provider != ProviderName {
+		t.Errorf("Expected provider %q, got %q", ProviderName, response.Meta.Provider)
 	}
 }
 
@@ -0,0 +1,445 @@
+// Package currencyapitest provides an in-memory implementation of currencyapi.Client
+// for tests and offline development.
+package currencyapitest
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// Endpoint identifies a currencyapi.Client method
+type Endpoint string
+
+// Endpoints of the currencyapi.Client interface
+const (
+	EndpointStatus     Endpoint = "status"
+	EndpointCurrencies Endpoint = "currencies"
+	EndpointLatest     Endpoint = "latest"
+	EndpointHistorical Endpoint = "historical"
+	EndpointConvert    Endpoint = "convert"
+)
+
+// ProviderName is the default provider reported in the meta of fake responses
+const ProviderName = "fake"
+
+// Call records a single call made to a FakeClient
+type Call struct {
+	Endpoint Endpoint
+	Params   interface{} // Parameters passed to the method (nil for Status)
+	At       time.Time
+}
+
+// FakeClient is an in-memory currencyapi.Client backed by a seeded rate table.
+// Rates for any base currency are derived from the table by cross rates.
+// It is safe for concurrent use.
+type FakeClient struct {
+	base        string
+	provider    string
+	rates       map[string]money.Amount
+	historical  map[string]map[string]money.Amount
+	currencies  map[string]currencyapi.CurrencyInfo
+	lastUpdated time.Time
+	quotaTotal  int
+	quotaUsed   int
+	errs        map[Endpoint]error
+	queued      map[Endpoint][]error
+	latency     map[Endpoint]time.Duration
+	calls       []Call
+	now         func() time.Time
+	mu          sync.Mutex
+}
+
+// NewFakeClient creates a fake client from a rate table. The table holds the value
+// of one unit of the base currency in every other currency.
+func NewFakeClient(base string, rates map[string]float64) *FakeClient {
+	f := &FakeClient{
+		base:       base,
+		provider:   ProviderName,
+		historical: make(map[string]map[string]money.Amount),
+		errs:       make(map[Endpoint]error),
+		queued:     make(map[Endpoint][]error),
+		latency:    make(map[Endpoint]time.Duration),
+		quotaTotal: 100000,
+		now:        time.Now,
+	}
+	f.SetRates(rates)
+	return f
+}
+
+// DefaultRates returns a USD based rate table suitable for offline development
+func DefaultRates() map[string]float64 {
+	return map[string]float64{
+		"EUR": 0.92,
+		"GBP": 0.79,
+		"UAH": 41.5,
+		"JPY": 149.8,
+		"CHF": 0.88,
+		"PLN": 3.98,
+		"CAD": 1.36,
+	}
+}
+
+// SetRates replaces the rate table and updates the last updated timestamp.
+// Rates are stored as the shortest decimals of the floats, e.g. 0.92 is exactly 0.92.
+func (f *FakeClient) SetRates(rates map[string]float64) {
+	f.SetRateAmounts(amounts(rates))
+}
+
+// SetRateAmounts replaces the rate table with exact amounts and updates the last updated timestamp
+func (f *FakeClient) SetRateAmounts(rates map[string]money.Amount) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rates = maps.Clone(rates)
+	f.lastUpdated = f.now().UTC()
+}
+
+// SetRate sets a single rate of the table and updates the last updated timestamp
+func (f *FakeClient) SetRate(code string, value float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rates[code] = money.NewFromFloat(value)
+	f.lastUpdated = f.now().UTC()
+}
+
+// SetLastUpdated sets the timestamp reported in the meta of latest responses
+func (f *FakeClient) SetLastUpdated(lastUpdated time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastUpdated = lastUpdated.UTC()
+}
+
+// SetProvider sets the provider name reported in the meta of rate responses
+func (f *FakeClient) SetProvider(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.provider = name
+}
+
+// SetHistoricalRates sets the rate table for a date (YYYY-MM-DD).
+// Dates without a table are served from the current rates.
+func (f *FakeClient) SetHistoricalRates(date string, rates map[string]float64) {
+	f.SetHistoricalRateAmounts(date, amounts(rates))
+}
+
+// SetHistoricalRateAmounts sets the rate table for a date (YYYY-MM-DD) with exact amounts
+func (f *FakeClient) SetHistoricalRateAmounts(date string, rates map[string]money.Amount) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.historical[date] = maps.Clone(rates)
+}
+
+// SetCurrencies sets the currency catalog returned by Currencies.
+// Without it, a minimal catalog is derived from the rate table.
+func (f *FakeClient) SetCurrencies(currencies map[string]currencyapi.CurrencyInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.currencies = make(map[string]currencyapi.CurrencyInfo, len(currencies))
+	for code, info := range currencies {
+		f.currencies[code] = info
+	}
+}
+
+// SetQuota sets the monthly quota reported by Status. Every successful call
+// other than Status spends one request.
+func (f *FakeClient) SetQuota(total, used int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.quotaTotal = total
+	f.quotaUsed = used
+}
+
+// SetError makes every call to the endpoint fail with err until it is cleared with nil
+func (f *FakeClient) SetError(endpoint Endpoint, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.errs, endpoint)
+		return
+	}
+	f.errs[endpoint] = err
+}
+
+// FailNext queues errors returned by the next calls to the endpoint, one per call
+func (f *FakeClient) FailNext(endpoint Endpoint, errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queued[endpoint] = append(f.queued[endpoint], errs...)
+}
+
+// SetLatency delays every call to the endpoint. A call that is still waiting when
+// its context is done fails like a real request would.
+func (f *FakeClient) SetLatency(endpoint Endpoint, latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency[endpoint] = latency
+}
+
+// Calls returns all recorded calls in order
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]Call, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// CallCount returns the number of recorded calls to the endpoint
+func (f *FakeClient) CallCount(endpoint Endpoint) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, call := range f.calls {
+		if call.Endpoint == endpoint {
+			count++
+		}
+	}
+	return count
+}
+
+// ResetCalls clears the recorded calls
+func (f *FakeClient) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// begin records a call, simulates latency and returns the scripted error, if any
+func (f *FakeClient) begin(ctx context.Context, endpoint Endpoint, params interface{}) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Endpoint: endpoint, Params: params, At: f.now()})
+	latency := f.latency[endpoint]
+
+	var err error
+	if queued := f.queued[endpoint]; len(queued) > 0 {
+		err = queued[0]
+		f.queued[endpoint] = queued[1:]
+	} else {
+		err = f.errs[endpoint]
+	}
+	f.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return &currencyapi.RequestError{Op: "execute_request", Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return &currencyapi.RequestError{Op: "execute_request", Err: err}
+	}
+	return err
+}
+
+// crossRates derives rates for a base currency from a table, defaulting to the table's base.
+// It must be called with the mutex held.
+func (f *FakeClient) crossRates(table map[string]money.Amount, base string, currencies []string) (map[string]currencyapi.RateInfo, error) {
+	if base == "" {
+		base = f.base
+	}
+
+	rates := make(map[string]currencyapi.RateInfo, len(table))
+	for code, value := range table {
+		rates[code] = currencyapi.RateInfo{Code: code, Value: value}
+	}
+	return currencyapi.CrossRates(rates, f.base, base, currencies)
+}
+
+// Status returns the configured quota
+func (f *FakeClient) Status(ctx context.Context) (*currencyapi.StatusResponse, error) {
+	if err := f.begin(ctx, EndpointStatus, nil); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var response currencyapi.StatusResponse
+	response.Quotas.Month.Total = f.quotaTotal
+	response.Quotas.Month.Used = f.quotaUsed
+	response.Quotas.Month.Remaining = max(f.quotaTotal-f.quotaUsed, 0)
+	return &response, nil
+}
+
+// Currencies returns the currency catalog
+func (f *FakeClient) Currencies(ctx context.Context, params *currencyapi.CurrenciesParams) (*currencyapi.CurrenciesResponse, error) {
+	if err := f.begin(ctx, EndpointCurrencies, params); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	catalog := f.currencies
+	if catalog == nil {
+		catalog = make(map[string]currencyapi.CurrencyInfo, len(f.rates)+1)
+		for _, code := range append([]string{f.base}, keys(f.rates)...) {
+			catalog[code] = currencyapi.CurrencyInfo{
+				Code:          code,
+				Symbol:        code,
+				SymbolNative:  code,
+				Name:          code,
+				NamePlural:    code,
+				DecimalDigits: 2,
+				Type:          "fiat",
+			}
+		}
+	}
+
+	response := &currencyapi.CurrenciesResponse{Data: make(map[string]currencyapi.CurrencyInfo)}
+	for code, info := range catalog {
+		if params != nil && params.Type != "" && info.Type != params.Type {
+			continue
+		}
+		if params != nil && len(params.Currencies) > 0 && !contains(params.Currencies, code) {
+			continue
+		}
+		response.Data[code] = info
+	}
+
+	f.quotaUsed++
+	return response, nil
+}
+
+// Latest returns the current rates for the requested base currency
+func (f *FakeClient) Latest(ctx context.Context, params *currencyapi.LatestParams) (*currencyapi.LatestResponse, error) {
+	if err := f.begin(ctx, EndpointLatest, params); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var base string
+	var currencies []string
+	if params != nil {
+		base, currencies = params.BaseCurrency, params.Currencies
+	}
+
+	rates, err := f.crossRates(f.rates, base, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.LatestResponse{Data: rates}
+	response.Meta.LastUpdatedAt = f.lastUpdated.Format(time.RFC3339)
+	response.Meta.Provider = f.provider
+
+	f.quotaUsed++
+	return response, nil
+}
+
+// Historical returns the rates seeded for the requested date
+func (f *FakeClient) Historical(ctx context.Context, params *currencyapi.HistoricalParams) (*currencyapi.HistoricalResponse, error) {
+	if err := f.begin(ctx, EndpointHistorical, params); err != nil {
+		return nil, err
+	}
+	if params == nil || params.Date == "" {
+		return nil, &currencyapi.ValidationError{
+			Field:   "date",
+			Message: "date parameter is required for historical endpoint",
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	table, ok := f.historical[params.Date]
+	if !ok {
+		table = f.rates
+	}
+
+	rates, err := f.crossRates(table, params.BaseCurrency, params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.HistoricalResponse{Data: rates}
+	response.Meta.LastUpdatedAt = params.Date + "T23:59:59Z"
+	response.Meta.Provider = f.provider
+
+	f.quotaUsed++
+	return response, nil
+}
+
+// Convert converts an amount using the current or historical rates
+func (f *FakeClient) Convert(ctx context.Context, params *currencyapi.ConvertParams) (*currencyapi.ConvertResponse, error) {
+	if err := f.begin(ctx, EndpointConvert, params); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		return nil, &currencyapi.ValidationError{
+			Field:   "params",
+			Message: "convert parameters are required",
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	table := f.rates
+	lastUpdated := f.lastUpdated.Format(time.RFC3339)
+	if historical, ok := f.historical[params.Date]; ok {
+		table = historical
+		lastUpdated = params.Date + "T23:59:59Z"
+	}
+
+	rates, err := f.crossRates(table, params.BaseCurrency, params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.ConvertResponse{Data: make(map[string]currencyapi.ConvertRateInfo, len(rates))}
+	response.Meta.LastUpdatedAt = lastUpdated
+	response.Meta.Provider = f.provider
+	for code, rate := range rates {
+		response.Data[code] = currencyapi.ConvertRateInfo{Code: code, Value: rate.Value.Mul(params.Value)}
+	}
+
+	f.quotaUsed++
+	return response, nil
+}
+
+// amounts converts a float rate table to exact amounts
+func amounts(rates map[string]float64) map[string]money.Amount {
+	table := make(map[string]money.Amount, len(rates))
+	for code, value := range rates {
+		table[code] = money.NewFromFloat(value)
+	}
+	return table
+}
+
+// keys returns the keys of a rate table
+func keys(table map[string]money.Amount) []string {
+	result := make([]string, 0, len(table))
+	for code := range table {
+		result = append(result, code)
+	}
+	return result
+}
+
+// contains reports whether the list holds the code, ignoring case
+func contains(list []string, code string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package currencyapitest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// The fake must be usable wherever a real client is expected
+var _ currencyapi.Client = (*currencyapitest.FakeClient)(nil)
+
+func TestFakeClientLatest(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", map[string]float64{"EUR": 0.8, "UAH": 40})
+	ctx := context.Background()
+
+	response, err := fake.Latest(ctx, &currencyapi.LatestParams{BaseCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+
+	want := map[string]string{"USD": "1.25", "EUR": "1", "UAH": "50"}
+	for code, value := range want {
+		if got := response.Data[code].Value.String(); got != value {
+			t.Errorf("Rate for %s = %s, want %s", code, got, value)
+		}
+	}
+	if response.Meta.LastUpdatedAt == "" {
+		t.Error("Expected last updated timestamp")
+	}
+	if response.Meta.Provider != currencyapitest.ProviderName {
+		t.Errorf("Expected provider %q, got %q", currencyapitest.ProviderName, response.Meta.Provider)
+	}
+
+	if _, err := fake.Latest(ctx, &currencyapi.LatestParams{BaseCurrency: "XYZ"}); !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected ValidationError for unknown base, got %v", err)
+	}
+}
+
+func TestFakeClientHistoricalAndConvert(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", map[string]float64{"EUR": 0.8})
+	fake.SetHistoricalRates("2024-01-02", map[string]float64{"EUR": 0.9})
+	ctx := context.Background()
+
+	historical, err := fake.Historical(ctx, &currencyapi.HistoricalParams{Date: "2024-01-02", Currencies: []string{"EUR"}})
+	if err != nil {
+		t.Fatalf("Historical() error = %v", err)
+	}
+	if historical.Data["EUR"].Value.String() != "0.9" {
+		t.Errorf("Historical EUR = %s, want 0.9", historical.Data["EUR"].Value)
+	}
+
+	if _, err := fake.Historical(ctx, nil); !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected ValidationError without date, got %v", err)
+	}
+
+	converted, err := fake.Convert(ctx, &currencyapi.ConvertParams{
+		BaseCurrency: "USD",
+		Currencies:   []string{"EUR"},
+		Value:        money.MustParse("100.10"),
+		Date:         "2024-01-02",
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if got := converted.Data["EUR"].Value.String(); got != "90.090" {
+		t.Errorf("Converted EUR = %s, want 90.090", got)
+	}
+}
+
+func TestFakeClientScriptedErrors(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	ctx := context.Background()
+
+	quotaErr := &currencyapi.APIError{StatusCode: 429, Code: "quota_exceeded"}
+	fake.FailNext(currencyapitest.EndpointLatest, quotaErr, &currencyapi.HTTPError{StatusCode: 503})
+
+	var apiErr *currencyapi.APIError
+	if _, err := fake.Latest(ctx, nil); !errors.As(err, &apiErr) || !apiErr.IsQuotaExceeded() {
+		t.Errorf("First call: expected quota error, got %v", err)
+	}
+	if _, err := fake.Latest(ctx, nil); !currencyapi.IsHTTPError(err) {
+		t.Errorf("Second call: expected HTTPError, got %v", err)
+	}
+	if _, err := fake.Latest(ctx, nil); err != nil {
+		t.Errorf("Third call: expected success, got %v", err)
+	}
+
+	// Queued errors are per endpoint
+	fake.SetError(currencyapitest.EndpointStatus, &currencyapi.HTTPError{StatusCode: 500})
+	if _, err := fake.Currencies(ctx, nil); err != nil {
+		t.Errorf("Currencies() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := fake.Status(ctx); !currencyapi.IsTemporaryError(err) {
+			t.Errorf("Status() expected temporary error, got %v", err)
+		}
+	}
+	fake.SetError(currencyapitest.EndpointStatus, nil)
+	if _, err := fake.Status(ctx); err != nil {
+		t.Errorf("Status() after clearing error = %v", err)
+	}
+}
+
+func TestFakeClientLatency(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	fake.SetLatency(currencyapitest.EndpointLatest, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := fake.Latest(ctx, nil)
+	if !currencyapi.IsRequestError(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected RequestError wrapping context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFakeClientRecordsCalls(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	fake.SetQuota(10, 0)
+	ctx := context.Background()
+
+	params := &currencyapi.LatestParams{BaseCurrency: "GBP"}
+	fake.Latest(ctx, params)
+	fake.Latest(ctx, nil)
+	fake.Currencies(ctx, &currencyapi.CurrenciesParams{Currencies: []string{"EUR"}})
+
+	calls := fake.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 calls, got %d", len(calls))
+	}
+	if calls[0].Endpoint != currencyapitest.EndpointLatest || calls[0].Params != params {
+		t.Errorf("Unexpected first call: %+v", calls[0])
+	}
+	if got := fake.CallCount(currencyapitest.EndpointLatest); got != 2 {
+		t.Errorf("CallCount(latest) = %d, want 2", got)
+	}
+
+	status, err := fake.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Quotas.Month.Used != 3 || status.Quotas.Month.Remaining != 7 {
+		t.Errorf("Unexpected quota: %+v", status.Quotas.Month)
+	}
+
+	fake.ResetCalls()
+	if len(fake.Calls()) != 0 {
+		t.Error("Expected no calls after ResetCalls()")
+	}
+}
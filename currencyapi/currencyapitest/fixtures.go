@@ -0,0 +1,89 @@
+package currencyapitest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// LoadFixtures creates a FakeClient from a fixtures directory using the CurrencyAPI JSON shapes:
+//
+//	latest.json                  latest response with rates against base (required)
+//	currencies.json              currencies response (optional)
+//	status.json                  status response, used for the monthly quota (optional)
+//	historical/YYYY-MM-DD.json   historical responses with rates against base (optional)
+func LoadFixtures(dir, base string) (*FakeClient, error) {
+	var latest currencyapi.LatestResponse
+	if err := readFixture(filepath.Join(dir, "latest.json"), &latest); err != nil {
+		return nil, err
+	}
+
+	fake := NewFakeClient(base, nil)
+	fake.SetRateAmounts(rateTable(latest.Data, base))
+	if lastUpdated, err := time.Parse(time.RFC3339, latest.Meta.LastUpdatedAt); err == nil {
+		fake.SetLastUpdated(lastUpdated)
+	}
+
+	var currencies currencyapi.CurrenciesResponse
+	err := readFixture(filepath.Join(dir, "currencies.json"), &currencies)
+	switch {
+	case err == nil:
+		fake.SetCurrencies(currencies.Data)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	var status currencyapi.StatusResponse
+	err = readFixture(filepath.Join(dir, "status.json"), &status)
+	switch {
+	case err == nil:
+		fake.SetQuota(status.Quotas.Month.Total, status.Quotas.Month.Used)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "historical", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		var historical currencyapi.HistoricalResponse
+		if err := readFixture(file, &historical); err != nil {
+			return nil, err
+		}
+		date := strings.TrimSuffix(filepath.Base(file), ".json")
+		fake.SetHistoricalRateAmounts(date, rateTable(historical.Data, base))
+	}
+
+	return fake, nil
+}
+
+// readFixture decodes a JSON fixture file
+func readFixture(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &currencyapi.ParseError{Endpoint: path, Err: err}
+	}
+	return nil
+}
+
+// rateTable converts response data to a rate table, leaving out the base currency itself
+func rateTable(data map[string]currencyapi.RateInfo, base string) map[string]money.Amount {
+	table := make(map[string]money.Amount, len(data))
+	for code, rate := range data {
+		if code != base {
+			table[code] = rate.Value
+		}
+	}
+	return table
+}
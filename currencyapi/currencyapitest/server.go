@@ -0,0 +1,216 @@
+package currencyapitest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// Fault is an error condition injected by a Server into every API response
+type Fault string
+
+// Faults supported by Server
+const (
+	FaultNone          Fault = ""
+	FaultUnauthorized  Fault = "401"
+	FaultRateLimited   Fault = "429"
+	FaultQuotaExceeded Fault = "quota"
+)
+
+// ParseFault parses a fault name as accepted by the stub server flags
+func ParseFault(name string) (Fault, error) {
+	switch fault := Fault(strings.ToLower(name)); fault {
+	case FaultNone, FaultUnauthorized, FaultRateLimited, FaultQuotaExceeded:
+		return fault, nil
+	case "none":
+		return FaultNone, nil
+	}
+	return FaultNone, errors.New("unknown fault " + strconv.Quote(name) + ", expected none, 401, 429 or quota")
+}
+
+// ServerOptions configures a Server
+type ServerOptions struct {
+	// APIKey is the key clients must send in the apikey header (any non-empty key if empty)
+	APIKey string
+	// Fault is the initial fault injected into responses
+	Fault Fault
+}
+
+// Server is an http.Handler that serves the CurrencyAPI JSON endpoints
+// (status, currencies, latest, historical and convert) from any currencyapi.Client.
+// Mount it under /v3/ with http.StripPrefix to mimic the real service.
+type Server struct {
+	client currencyapi.Client
+	apiKey string
+	fault  Fault
+	mux    *http.ServeMux
+	mu     sync.RWMutex
+}
+
+// NewServer creates a Server backed by the given client
+func NewServer(client currencyapi.Client, opts ServerOptions) *Server {
+	s := &Server{
+		client: client,
+		apiKey: opts.APIKey,
+		fault:  opts.Fault,
+		mux:    http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/status", s.status)
+	s.mux.HandleFunc("/currencies", s.currencies)
+	s.mux.HandleFunc("/latest", s.latest)
+	s.mux.HandleFunc("/historical", s.historical)
+	s.mux.HandleFunc("/convert", s.convert)
+	return s
+}
+
+// SetFault changes the fault injected into responses
+func (s *Server) SetFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fault = fault
+}
+
+// Fault returns the fault currently injected into responses
+func (s *Server) Fault() Fault {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fault
+}
+
+// ServeHTTP authenticates the request, injects the configured fault and serves the endpoint
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("apikey")
+	if key == "" {
+		key = r.URL.Query().Get("apikey")
+	}
+	if key == "" || (s.apiKey != "" && key != s.apiKey) {
+		writeAPIError(w, http.StatusUnauthorized, "invalid_api_key", "Invalid authentication credentials")
+		return
+	}
+
+	switch s.Fault() {
+	case FaultUnauthorized:
+		writeAPIError(w, http.StatusUnauthorized, "invalid_api_key", "Invalid authentication credentials")
+		return
+	case FaultRateLimited:
+		w.Header().Set("Retry-After", "1")
+		writeAPIError(w, http.StatusTooManyRequests, "rate_limit_exceeded", "API rate limit exceeded")
+		return
+	case FaultQuotaExceeded:
+		writeAPIError(w, http.StatusTooManyRequests, "quota_exceeded", "You used all your monthly requests")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	response, err := s.client.Status(r.Context())
+	writeResponse(w, response, err)
+}
+
+func (s *Server) currencies(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	response, err := s.client.Currencies(r.Context(), &currencyapi.CurrenciesParams{
+		Currencies: splitList(q.Get("currencies")),
+		Type:       q.Get("type"),
+	})
+	writeResponse(w, response, err)
+}
+
+func (s *Server) latest(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	response, err := s.client.Latest(r.Context(), &currencyapi.LatestParams{
+		BaseCurrency: q.Get("base_currency"),
+		Currencies:   splitList(q.Get("currencies")),
+	})
+	writeResponse(w, response, err)
+}
+
+func (s *Server) historical(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	response, err := s.client.Historical(r.Context(), &currencyapi.HistoricalParams{
+		Date:         q.Get("date"),
+		BaseCurrency: q.Get("base_currency"),
+		Currencies:   splitList(q.Get("currencies")),
+	})
+	writeResponse(w, response, err)
+}
+
+func (s *Server) convert(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var value money.Amount
+	if raw := q.Get("value"); raw != "" {
+		var err error
+		value, err = money.Parse(raw)
+		if err != nil {
+			writeAPIError(w, http.StatusUnprocessableEntity, "validation_error", "The value must be a number")
+			return
+		}
+	}
+
+	response, err := s.client.Convert(r.Context(), &currencyapi.ConvertParams{
+		BaseCurrency: q.Get("base_currency"),
+		Currencies:   splitList(q.Get("currencies")),
+		Value:        value,
+		Date:         q.Get("date"),
+	})
+	writeResponse(w, response, err)
+}
+
+// writeResponse writes a successful response as JSON or maps the error to an API error response
+func writeResponse(w http.ResponseWriter, response interface{}, err error) {
+	if err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var validationErr *currencyapi.ValidationError
+	if errors.As(err, &validationErr) {
+		writeAPIError(w, http.StatusUnprocessableEntity, "validation_error", validationErr.Error())
+		return
+	}
+
+	var apiErr *currencyapi.APIError
+	if errors.As(err, &apiErr) {
+		writeAPIError(w, apiErr.StatusCode, apiErr.Code, apiErr.Message)
+		return
+	}
+
+	var httpErr *currencyapi.HTTPError
+	if errors.As(err, &httpErr) {
+		w.WriteHeader(httpErr.StatusCode)
+		w.Write([]byte(httpErr.Body))
+		return
+	}
+
+	writeAPIError(w, http.StatusInternalServerError, "server_error", err.Error())
+}
+
+// writeAPIError writes an error in the CurrencyAPI error format
+func writeAPIError(w http.ResponseWriter, statusCode int, code, message string) {
+	var response currencyapi.APIErrorResponse
+	response.Error.Code = code
+	response.Error.Message = message
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+// splitList splits a comma-separated query parameter
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,140 @@
+package currencyapitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// newStubServer starts a stub server and returns a real HTTP client pointed at it
+func newStubServer(t *testing.T, opts currencyapitest.ServerOptions) (*currencyapitest.Server, currencyapi.Client) {
+	t.Helper()
+
+	fake := currencyapitest.NewFakeClient("USD", map[string]float64{"EUR": 0.8, "UAH": 40})
+	fake.SetHistoricalRates("2024-01-02", map[string]float64{"EUR": 0.9, "UAH": 38})
+
+	stub := currencyapitest.NewServer(fake, opts)
+	server := httptest.NewServer(http.StripPrefix("/v3", stub))
+	t.Cleanup(server.Close)
+
+	client, err := currencyapi.NewHttpApiClient("test-key", currencyapi.WithBaseURL(server.URL+"/v3/"))
+	if err != nil {
+		t.Fatalf("NewHttpApiClient() error = %v", err)
+	}
+	return stub, client
+}
+
+func TestServerEndpoints(t *testing.T) {
+	_, client := newStubServer(t, currencyapitest.ServerOptions{})
+	ctx := context.Background()
+
+	if _, err := client.Status(ctx); err != nil {
+		t.Errorf("Status() error = %v", err)
+	}
+
+	currencies, err := client.Currencies(ctx, &currencyapi.CurrenciesParams{Currencies: []string{"EUR"}})
+	if err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+	if len(currencies.Data) != 1 || currencies.Data["EUR"].Code != "EUR" {
+		t.Errorf("Unexpected currencies: %+v", currencies.Data)
+	}
+
+	latest, err := client.Latest(ctx, &currencyapi.LatestParams{BaseCurrency: "EUR", Currencies: []string{"USD", "UAH"}})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Data["UAH"].Value.String() != "50" || latest.Data["USD"].Value.String() != "1.25" {
+		t.Errorf("Unexpected latest rates: %+v", latest.Data)
+	}
+
+	historical, err := client.Historical(ctx, &currencyapi.HistoricalParams{Date: "2024-01-02", Currencies: []string{"EUR"}})
+	if err != nil {
+		t.Fatalf("Historical() error = %v", err)
+	}
+	if historical.Data["EUR"].Value.String() != "0.9" {
+		t.Errorf("Unexpected historical rates: %+v", historical.Data)
+	}
+
+	converted, err := client.Convert(ctx, &currencyapi.ConvertParams{BaseCurrency: "USD", Currencies: []string{"UAH"}, Value: money.MustParse("2.5")})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !converted.Data["UAH"].Value.Equal(money.New(100, 0)) {
+		t.Errorf("Unexpected conversion: %+v", converted.Data)
+	}
+}
+
+func TestServerValidationErrors(t *testing.T) {
+	_, client := newStubServer(t, currencyapitest.ServerOptions{})
+
+	_, err := client.Latest(context.Background(), &currencyapi.LatestParams{BaseCurrency: "XYZ"})
+	var apiErr *currencyapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 APIError, got %v", err)
+	}
+}
+
+func TestServerAPIKey(t *testing.T) {
+	_, client := newStubServer(t, currencyapitest.ServerOptions{APIKey: "other-key"})
+
+	_, err := client.Latest(context.Background(), nil)
+	var apiErr *currencyapi.APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsInvalidAPIKey() {
+		t.Errorf("Expected invalid API key error, got %v", err)
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	stub, client := newStubServer(t, currencyapitest.ServerOptions{Fault: currencyapitest.FaultUnauthorized})
+	ctx := context.Background()
+
+	var apiErr *currencyapi.APIError
+	if _, err := client.Latest(ctx, nil); !errors.As(err, &apiErr) || !apiErr.IsInvalidAPIKey() {
+		t.Errorf("Expected invalid API key error, got %v", err)
+	}
+
+	stub.SetFault(currencyapitest.FaultRateLimited)
+	_, err := client.Latest(ctx, nil)
+	if !currencyapi.IsTemporaryError(err) {
+		t.Errorf("Expected temporary error, got %v", err)
+	}
+	if retryAfter, ok := currencyapi.GetRetryAfter(err); !ok || retryAfter.Seconds() != 1 {
+		t.Errorf("Expected Retry-After of 1s, got %v", retryAfter)
+	}
+
+	stub.SetFault(currencyapitest.FaultQuotaExceeded)
+	if _, err := client.Latest(ctx, nil); !errors.As(err, &apiErr) || !apiErr.IsQuotaExceeded() {
+		t.Errorf("Expected quota exceeded error, got %v", err)
+	}
+
+	stub.SetFault(currencyapitest.FaultNone)
+	if _, err := client.Latest(ctx, nil); err != nil {
+		t.Errorf("Latest() without fault error = %v", err)
+	}
+}
+
+func TestParseFault(t *testing.T) {
+	tests := map[string]currencyapitest.Fault{
+		"":      currencyapitest.FaultNone,
+		"none":  currencyapitest.FaultNone,
+		"401":   currencyapitest.FaultUnauthorized,
+		"429":   currencyapitest.FaultRateLimited,
+		"QUOTA": currencyapitest.FaultQuotaExceeded,
+	}
+	for name, want := range tests {
+		if got, err := currencyapitest.ParseFault(name); err != nil || got != want {
+			t.Errorf("ParseFault(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	if _, err := currencyapitest.ParseFault("500"); err == nil {
+		t.Error("Expected error for unknown fault")
+	}
+}
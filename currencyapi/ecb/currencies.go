@@ -0,0 +1,69 @@
+package ecb
+
+import "github.com/BohdanKyryliuk/golang/currencyapi"
+
+// currencyNames holds the names of the currencies quoted by the ECB
+var currencyNames = map[string]string{
+	"EUR": "Euro",
+	"USD": "US Dollar",
+	"JPY": "Japanese Yen",
+	"BGN": "Bulgarian Lev",
+	"CZK": "Czech Koruna",
+	"DKK": "Danish Krone",
+	"GBP": "British Pound Sterling",
+	"HUF": "Hungarian Forint",
+	"PLN": "Polish Zloty",
+	"RON": "Romanian Leu",
+	"SEK": "Swedish Krona",
+	"CHF": "Swiss Franc",
+	"ISK": "Icelandic Krona",
+	"NOK": "Norwegian Krone",
+	"TRY": "Turkish Lira",
+	"AUD": "Australian Dollar",
+	"BRL": "Brazilian Real",
+	"CAD": "Canadian Dollar",
+	"CNY": "Chinese Yuan",
+	"HKD": "Hong Kong Dollar",
+	"IDR": "Indonesian Rupiah",
+	"ILS": "Israeli New Shekel",
+	"INR": "Indian Rupee",
+	"KRW": "South Korean Won",
+	"MXN": "Mexican Peso",
+	"MYR": "Malaysian Ringgit",
+	"NZD": "New Zealand Dollar",
+	"PHP": "Philippine Peso",
+	"SGD": "Singapore Dollar",
+	"THB": "Thai Baht",
+	"ZAR": "South African Rand",
+}
+
+// zeroDecimalCurrencies lists currencies without minor units
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"ISK": true,
+	"KRW": true,
+	"IDR": true,
+}
+
+// currencyInfo describes a currency quoted by the ECB
+func currencyInfo(code string) currencyapi.CurrencyInfo {
+	name, ok := currencyNames[code]
+	if !ok {
+		name = code
+	}
+
+	decimalDigits := 2
+	if zeroDecimalCurrencies[code] {
+		decimalDigits = 0
+	}
+
+	return currencyapi.CurrencyInfo{
+		Code:          code,
+		Name:          name,
+		NamePlural:    name,
+		Symbol:        code,
+		SymbolNative:  code,
+		DecimalDigits: decimalDigits,
+		Type:          "fiat",
+	}
+}
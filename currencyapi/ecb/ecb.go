@@ -0,0 +1,349 @@
+// Package ecb implements currencyapi.Client on top of the euro foreign exchange
+// reference rates published by the European Central Bank.
+// See: https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html
+package ecb
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+const (
+	// DefaultBaseURL is the default location of the ECB reference rate feeds
+	DefaultBaseURL = "https://www.ecb.europa.eu/stats/eurofxref/"
+	// DefaultTimeout is the default HTTP client timeout
+	DefaultTimeout = 10 * time.Second
+	// ProviderName identifies the ECB in ResponseMeta.Provider
+	ProviderName = "ecb"
+
+	// DailyFeed holds the rates of the latest working day
+	DailyFeed = "eurofxref-daily.xml"
+	// NinetyDayFeed holds the rates of the last 90 days
+	NinetyDayFeed = "eurofxref-hist-90d.xml"
+	// HistoryFeed holds all rates since 1999
+	HistoryFeed = "eurofxref-hist.xml"
+
+	// baseCurrency is the currency all ECB rates are quoted against
+	baseCurrency = "EUR"
+	// defaultBaseCurrency matches the CurrencyAPI default base currency
+	defaultBaseCurrency = "USD"
+	// dateLayout is the date format used by the feeds and by HistoricalParams
+	dateLayout = "2006-01-02"
+)
+
+// frankfurt is the time zone of the ECB, with a fixed CET fallback when the system
+// has no time zone database
+var frankfurt = func() *time.Location {
+	if location, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		return location
+	}
+	return time.FixedZone("CET", 60*60)
+}()
+
+// publicationHour is the hour in Frankfurt at which the ECB publishes the reference rates
+const publicationHour = 16
+
+// publishedAt returns the publication time of the rates of a reference date as an
+// RFC 3339 UTC timestamp. The feeds only carry the date, so this is the 16:00
+// Frankfurt time the ECB announces rather than the exact time of publication.
+func publishedAt(date string) string {
+	t, err := time.ParseInLocation(dateLayout, date, frankfurt)
+	if err != nil {
+		return date
+	}
+	return t.Add(publicationHour * time.Hour).UTC().Format(time.RFC3339)
+}
+
+// Client is a currencyapi.Client backed by the ECB reference rate feeds.
+// The ECB publishes rates once per working day and has no quota, so Status
+// reports an empty quota. Wrap it in currencyapi.NewCachingClient to avoid
+// downloading the history feeds for every historical request.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// Option is a function that configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets a custom location of the feeds
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient creates a new ECB client with the provided options
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL: DefaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+		now: time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// envelope is the XML document published by the ECB
+type envelope struct {
+	Cube struct {
+		Days []day `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// day holds the reference rates of a single working day
+type day struct {
+	Time  string `xml:"time,attr"`
+	Rates []struct {
+		Currency string       `xml:"currency,attr"`
+		Rate     money.Amount `xml:"rate,attr"`
+	} `xml:"Cube"`
+}
+
+// table returns the rates of the day keyed by currency code
+func (d day) table() map[string]currencyapi.RateInfo {
+	rates := make(map[string]currencyapi.RateInfo, len(d.Rates))
+	for _, rate := range d.Rates {
+		rates[rate.Currency] = currencyapi.RateInfo{Code: rate.Currency, Value: rate.Rate}
+	}
+	return rates
+}
+
+// fetchFeed downloads and parses a feed, returning its days from newest to oldest
+func (c *Client) fetchFeed(ctx context.Context, feed string) ([]day, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+feed, nil)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "create_request",
+			Err: err,
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "execute_request",
+			Err: err,
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "read_response",
+			Err: err,
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &currencyapi.HTTPError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+		}
+	}
+
+	var doc envelope
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, &currencyapi.ParseError{
+			Endpoint: feed,
+			Err:      err,
+		}
+	}
+	if len(doc.Cube.Days) == 0 {
+		return nil, &currencyapi.ParseError{
+			Endpoint: feed,
+			Err:      io.ErrUnexpectedEOF,
+		}
+	}
+
+	days := doc.Cube.Days
+	sort.Slice(days, func(i, j int) bool { return days[i].Time > days[j].Time })
+	return days, nil
+}
+
+// latestDay returns the most recent published rates
+func (c *Client) latestDay(ctx context.Context) (day, error) {
+	days, err := c.fetchFeed(ctx, DailyFeed)
+	if err != nil {
+		return day{}, err
+	}
+	return days[0], nil
+}
+
+// historicalDay returns the rates published for a date. Weekends and holidays have
+// no rates of their own, so the last rates published before the date are used.
+func (c *Client) historicalDay(ctx context.Context, date string) (day, error) {
+	requested, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return day{}, &currencyapi.ValidationError{
+			Field:   "date",
+			Message: "date must be in format YYYY-MM-DD",
+		}
+	}
+
+	// The 90 day feed is much smaller than the full history
+	feed := HistoryFeed
+	if c.now().Sub(requested) < 85*24*time.Hour {
+		feed = NinetyDayFeed
+	}
+
+	days, err := c.fetchFeed(ctx, feed)
+	if err != nil {
+		return day{}, err
+	}
+
+	for _, d := range days {
+		if d.Time <= date {
+			return d, nil
+		}
+	}
+	return day{}, &currencyapi.ValidationError{
+		Field:   "date",
+		Message: "no reference rates published on or before " + date,
+	}
+}
+
+// rebase converts EUR based rates to the requested base currency
+func rebase(d day, base string, currencies []string) (map[string]currencyapi.RateInfo, error) {
+	if base == "" {
+		base = defaultBaseCurrency
+	}
+	return currencyapi.CrossRates(d.table(), baseCurrency, base, currencies)
+}
+
+// Status returns an empty quota because the ECB feeds are free and unlimited
+func (c *Client) Status(ctx context.Context) (*currencyapi.StatusResponse, error) {
+	return &currencyapi.StatusResponse{}, nil
+}
+
+// Currencies returns the currencies with published reference rates
+func (c *Client) Currencies(ctx context.Context, params *currencyapi.CurrenciesParams) (*currencyapi.CurrenciesResponse, error) {
+	latest, err := c.latestDay(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil && params.Type != "" && params.Type != "fiat" {
+		return &currencyapi.CurrenciesResponse{Data: map[string]currencyapi.CurrencyInfo{}}, nil
+	}
+
+	codes := []string{baseCurrency}
+	for _, rate := range latest.Rates {
+		codes = append(codes, rate.Currency)
+	}
+
+	response := &currencyapi.CurrenciesResponse{Data: make(map[string]currencyapi.CurrencyInfo, len(codes))}
+	for _, code := range codes {
+		if params != nil && len(params.Currencies) > 0 && !slices.Contains(params.Currencies, code) {
+			continue
+		}
+		response.Data[code] = currencyInfo(code)
+	}
+	return response, nil
+}
+
+// Latest returns the reference rates of the latest working day
+func (c *Client) Latest(ctx context.Context, params *currencyapi.LatestParams) (*currencyapi.LatestResponse, error) {
+	latest, err := c.latestDay(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var base string
+	var currencies []string
+	if params != nil {
+		base, currencies = params.BaseCurrency, params.Currencies
+	}
+
+	rates, err := rebase(latest, base, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.LatestResponse{Data: rates}
+	response.Meta.LastUpdatedAt = publishedAt(latest.Time)
+	response.Meta.Provider = ProviderName
+	return response, nil
+}
+
+// Historical returns the reference rates for a specific date
+func (c *Client) Historical(ctx context.Context, params *currencyapi.HistoricalParams) (*currencyapi.HistoricalResponse, error) {
+	if params == nil || params.Date == "" {
+		return nil, &currencyapi.ValidationError{
+			Field:   "date",
+			Message: "date parameter is required for historical endpoint",
+		}
+	}
+
+	historical, err := c.historicalDay(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := rebase(historical, params.BaseCurrency, params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.HistoricalResponse{Data: rates}
+	response.Meta.LastUpdatedAt = publishedAt(historical.Time)
+	response.Meta.Provider = ProviderName
+	return response, nil
+}
+
+// Convert converts an amount using the latest or historical reference rates
+func (c *Client) Convert(ctx context.Context, params *currencyapi.ConvertParams) (*currencyapi.ConvertResponse, error) {
+	if params == nil {
+		return nil, &currencyapi.ValidationError{
+			Field:   "params",
+			Message: "convert parameters are required",
+		}
+	}
+
+	var d day
+	var err error
+	if params.Date != "" {
+		d, err = c.historicalDay(ctx, params.Date)
+	} else {
+		d, err = c.latestDay(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := rebase(d, params.BaseCurrency, params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.ConvertResponse{Data: make(map[string]currencyapi.ConvertRateInfo, len(rates))}
+	response.Meta.LastUpdatedAt = publishedAt(d.Time)
+	response.Meta.Provider = ProviderName
+	for code, rate := range rates {
+		response.Data[code] = currencyapi.ConvertRateInfo{Code: code, Value: rate.Value.Mul(params.Value)}
+	}
+	return response, nil
+}
@@ -0,0 +1,216 @@
+package ecb
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+var _ currencyapi.Client = (*Client)(nil)
+
+// newTestClient serves the recorded feeds in testdata and records requested feeds
+func newTestClient(t *testing.T) (*Client, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requested []string
+	files := http.FileServer(http.Dir("testdata"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path[1:])
+		mu.Unlock()
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(WithBaseURL(server.URL + "/"))
+	client.now = func() time.Time { return time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC) }
+
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func assertRate(t *testing.T, rates map[string]currencyapi.RateInfo, code string, want float64) {
+	t.Helper()
+	rate, ok := rates[code]
+	if !ok {
+		t.Errorf("Missing rate for %s", code)
+		return
+	}
+	if rate.Code != code || math.Abs(rate.Value.Float64()-want) > 1e-9 {
+		t.Errorf("Rate for %s = %+v, want %v", code, rate, want)
+	}
+}
+
+func TestClientLatest(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	// Rates against the euro are returned as published
+	response, err := client.Latest(ctx, &currencyapi.LatestParams{BaseCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if len(response.Data) != 6 {
+		t.Errorf("Expected 6 rates, got %d", len(response.Data))
+	}
+	assertRate(t, response.Data, "EUR", 1)
+	assertRate(t, response.Data, "USD", 1.1645)
+	if response.Meta.LastUpdatedAt != "2025-12-05T15:00:00Z" {
+		t.Errorf("Unexpected last updated timestamp: %q", response.Meta.LastUpdatedAt)
+	}
+
+	// Other base currencies are derived by cross rates, USD by default
+	response, err = client.Latest(ctx, &currencyapi.LatestParams{Currencies: []string{"EUR", "GBP"}})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if len(response.Data) != 2 {
+		t.Errorf("Expected 2 rates, got %d", len(response.Data))
+	}
+	assertRate(t, response.Data, "EUR", 1/1.1645)
+	assertRate(t, response.Data, "GBP", 0.87338/1.1645)
+
+	if _, err := client.Latest(ctx, &currencyapi.LatestParams{BaseCurrency: "UAH"}); !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected ValidationError for unquoted base, got %v", err)
+	}
+}
+
+func TestClientHistorical(t *testing.T) {
+	tests := []struct {
+		name    string
+		date    string
+		feed    string
+		day     string
+		wantJPY float64
+		wantErr bool
+	}{
+		{name: "recent date uses 90 day feed", date: "2025-12-04", feed: NinetyDayFeed, day: "2025-12-04", wantJPY: 180.98 / 1.1662},
+		{name: "weekend uses previous working day", date: "2025-11-30", feed: NinetyDayFeed, day: "2025-11-28", wantJPY: 181.12 / 1.1590},
+		{name: "old date uses full history", date: "2020-01-02", feed: HistoryFeed, day: "2020-01-02", wantJPY: 121.75 / 1.1193},
+		{name: "before first publication", date: "1998-12-31", feed: HistoryFeed, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requested := newTestClient(t)
+
+			response, err := client.Historical(context.Background(), &currencyapi.HistoricalParams{
+				Date:         tt.date,
+				BaseCurrency: "USD",
+				Currencies:   []string{"JPY"},
+			})
+			if got := requested(); len(got) != 1 || got[0] != tt.feed {
+				t.Errorf("Requested feeds %v, want [%s]", got, tt.feed)
+			}
+			if tt.wantErr {
+				if !currencyapi.IsValidationError(err) {
+					t.Errorf("Expected ValidationError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Historical() error = %v", err)
+			}
+
+			assertRate(t, response.Data, "JPY", tt.wantJPY)
+			if response.Meta.LastUpdatedAt != publishedAt(tt.day) {
+				t.Errorf("Unexpected last updated timestamp: %q", response.Meta.LastUpdatedAt)
+			}
+		})
+	}
+}
+
+func TestClientHistoricalValidation(t *testing.T) {
+	client, requested := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Historical(ctx, nil); !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected ValidationError without date, got %v", err)
+	}
+	if _, err := client.Historical(ctx, &currencyapi.HistoricalParams{Date: "05.12.2025"}); !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected ValidationError for malformed date, got %v", err)
+	}
+	if got := requested(); len(got) != 0 {
+		t.Errorf("Invalid requests must not fetch feeds, got %v", got)
+	}
+}
+
+func TestClientConvert(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	response, err := client.Convert(context.Background(), &currencyapi.ConvertParams{
+		BaseCurrency: "GBP",
+		Currencies:   []string{"EUR"},
+		Value:        money.MustParse("87.338"),
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if got := response.Data["EUR"].Value.Float64(); math.Abs(got-100) > 1e-9 {
+		t.Errorf("Converted EUR = %v, want 100", got)
+	}
+}
+
+func TestClientCurrencies(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	response, err := client.Currencies(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+	if len(response.Data) != 6 {
+		t.Errorf("Expected 6 currencies, got %d", len(response.Data))
+	}
+	if jpy := response.Data["JPY"]; jpy.Name != "Japanese Yen" || jpy.DecimalDigits != 0 {
+		t.Errorf("Unexpected JPY info: %+v", jpy)
+	}
+
+	status, err := client.Status(context.Background())
+	if err != nil || status.Quotas.Month.Total != 0 {
+		t.Errorf("Status() = %+v, %v, want empty quota", status, err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+DailyFeed {
+			w.Write([]byte("<html>maintenance</html>"))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL + "/"))
+	ctx := context.Background()
+
+	if _, err := client.Latest(ctx, nil); !currencyapi.IsParseError(err) {
+		t.Errorf("Expected ParseError, got %v", err)
+	}
+	if _, err := client.Historical(ctx, &currencyapi.HistoricalParams{Date: "2020-01-02"}); !currencyapi.IsTemporaryError(err) {
+		t.Errorf("Expected temporary HTTPError, got %v", err)
+	}
+}
+
+func TestPublishedAt(t *testing.T) {
+	tests := map[string]string{
+		"2025-12-05": "2025-12-05T15:00:00Z", // CET
+		"2025-07-04": "2025-07-04T14:00:00Z", // CEST
+	}
+	for date, want := range tests {
+		if got := publishedAt(date); got != want {
+			t.Errorf("publishedAt(%q) = %q, want %q", date, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ValidationError represents an input validation error
@@ -34,6 +35,7 @@ func (e *RequestError) Unwrap() error {
 type HTTPError struct {
 	StatusCode int
 	Body       string
+	RetryAfter time.Duration // Delay requested by the Retry-After header, if any
 }
 
 func (e *HTTPError) Error() string {
@@ -61,6 +63,7 @@ type APIError struct {
 	Code       string
 	Message    string
 	Info       string
+	RetryAfter time.Duration // Delay requested by the Retry-After header, if any
 }
 
 func (e *APIError) Error() string {
@@ -94,6 +97,17 @@ func (e *ParseError) Unwrap() error {
 	return e.Err
 }
 
+// QuotaReservedError is returned by a QuotaGuard when a non-essential request
+// would spend the part of the monthly quota reserved for essential calls
+type QuotaReservedError struct {
+	Remaining int // Remaining monthly requests
+	Reserve   int // Requests kept for essential calls
+}
+
+func (e *QuotaReservedError) Error() string {
+	return fmt.Sprintf("quota reserved: %d requests remaining, %d kept for essential calls", e.Remaining, e.Reserve)
+}
+
 // APIErrorResponse represents the error response structure from the API
 type APIErrorResponse struct {
 	Error struct {
@@ -135,6 +149,18 @@ func IsParseError(err error) bool {
 	return errors.As(err, &pe)
 }
 
+// IsQuotaReservedError checks if the error was caused by the quota guard
+func IsQuotaReservedError(err error) bool {
+	var qe *QuotaReservedError
+	return errors.As(err, &qe)
+}
+
+// IsQuotaExceededError checks if the error reports an exhausted API quota
+func IsQuotaExceededError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsQuotaExceeded()
+}
+
 // GetHTTPStatusCode extracts the HTTP status code from an error if available
 func GetHTTPStatusCode(err error) (int, bool) {
 	var httpErr *HTTPError
@@ -148,6 +174,19 @@ func GetHTTPStatusCode(err error) (int, bool) {
 	return 0, false
 }
 
+// GetRetryAfter extracts the delay requested by the Retry-After header from an error if available
+func GetRetryAfter(err error) (time.Duration, bool) {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		return httpErr.RetryAfter, true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	return 0, false
+}
+
 // IsTemporaryError returns true if the error might be resolved by retrying
 func IsTemporaryError(err error) bool {
 	var httpErr *HTTPError
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
 )
 
 // ExampleNewHttpApiClient demonstrates how to create a new CurrencyAPI client
@@ -24,6 +25,7 @@ func ExampleNewHttpApiClient() {
 		"your-api-key",
 		currencyapi.WithTimeout(30*time.Second),
 		currencyapi.WithBaseURL("https://api.currencyapi.com/v3/"),
+		currencyapi.WithRetryPolicy(currencyapi.DefaultRetryPolicy()),
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -58,7 +60,7 @@ func ExampleClient_Latest() {
 
 	// Use the response
 	for code, rate := range response.Data {
-		fmt.Printf("%s: %f\n", code, rate.Value)
+		fmt.Printf("%s: %s\n", code, rate.Value)
 	}
 }
 
@@ -76,7 +78,7 @@ func ExampleClient_Convert() {
 	response, err := client.Convert(ctx, &currencyapi.ConvertParams{
 		BaseCurrency: "USD",
 		Currencies:   []string{"EUR", "UAH"},
-		Value:        100.0,
+		Value:        money.New(100, 0),
 	})
 
 	if err != nil {
@@ -84,9 +86,10 @@ func ExampleClient_Convert() {
 		return
 	}
 
+	// Converted amounts are exact; round them to cents for display
 	fmt.Printf("Converting 100 USD:\n")
 	for code, rate := range response.Data {
-		fmt.Printf("  %s: %f\n", code, rate.Value)
+		fmt.Printf("  %s: %s\n", code, rate.Value.Round(2, money.HalfEven))
 	}
 }
 
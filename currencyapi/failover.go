@@ -0,0 +1,148 @@
+package currencyapi
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverCooldown is how long a failed provider is skipped by default
+const DefaultFailoverCooldown = time.Minute
+
+// FailoverClient is a Client that tries providers in order. When a provider fails with
+// a temporary error or an exhausted quota, it cools down and the next provider is tried.
+// Cooling down providers are only used once every healthy provider has failed.
+// The provider that served a rates response is reported in its ResponseMeta.
+type FailoverClient struct {
+	providers []Client
+	cooldown  time.Duration
+	until     []time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewFailoverClient creates a client that prefers the primary provider and
+// falls back to the secondaries in order
+func NewFailoverClient(primary Client, secondaries ...Client) (*FailoverClient, error) {
+	providers := append([]Client{primary}, secondaries...)
+	for _, provider := range providers {
+		if provider == nil {
+			return nil, &ValidationError{Field: "providers", Message: "client is required"}
+		}
+	}
+
+	return &FailoverClient{
+		providers: providers,
+		cooldown:  DefaultFailoverCooldown,
+		until:     make([]time.Time, len(providers)),
+		now:       time.Now,
+	}, nil
+}
+
+// SetCooldown changes how long a failed provider is skipped
+func (f *FailoverClient) SetCooldown(cooldown time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cooldown = cooldown
+}
+
+// CoolingDown reports whether the provider at index i (0 is the primary) is currently skipped
+func (f *FailoverClient) CoolingDown(i int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now().Before(f.until[i])
+}
+
+// order returns the provider indexes to try: healthy providers first, then cooling down ones
+func (f *FailoverClient) order() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	order := make([]int, 0, len(f.providers))
+	var cooling []int
+	for i := range f.providers {
+		if now.Before(f.until[i]) {
+			cooling = append(cooling, i)
+		} else {
+			order = append(order, i)
+		}
+	}
+	return append(order, cooling...)
+}
+
+// markFailed starts the cooldown of a provider
+func (f *FailoverClient) markFailed(i int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	log.Printf("Failover: provider %d failed, cooling down for %v: %v", i, f.cooldown, err)
+	f.until[i] = f.now().Add(f.cooldown)
+}
+
+// markHealthy ends the cooldown of a provider
+func (f *FailoverClient) markHealthy(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.until[i] = time.Time{}
+}
+
+// shouldFailover reports whether another provider may succeed where one failed
+func shouldFailover(err error) bool {
+	return IsTemporaryError(err) || IsQuotaExceededError(err) || IsQuotaReservedError(err)
+}
+
+// failover calls providers in order until one succeeds or fails with a permanent error
+func failover[T any](ctx context.Context, f *FailoverClient, call func(Client) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for _, i := range f.order() {
+		response, err := call(f.providers[i])
+		if err == nil {
+			f.markHealthy(i)
+			return response, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil || !shouldFailover(err) {
+			return zero, err
+		}
+		f.markFailed(i, err)
+	}
+	return zero, lastErr
+}
+
+// Status returns the status of the first available provider
+func (f *FailoverClient) Status(ctx context.Context) (*StatusResponse, error) {
+	return failover(ctx, f, func(c Client) (*StatusResponse, error) {
+		return c.Status(ctx)
+	})
+}
+
+// Currencies returns available currencies
+func (f *FailoverClient) Currencies(ctx context.Context, params *CurrenciesParams) (*CurrenciesResponse, error) {
+	return failover(ctx, f, func(c Client) (*CurrenciesResponse, error) {
+		return c.Currencies(ctx, params)
+	})
+}
+
+// Latest returns the latest exchange rates
+func (f *FailoverClient) Latest(ctx context.Context, params *LatestParams) (*LatestResponse, error) {
+	return failover(ctx, f, func(c Client) (*LatestResponse, error) {
+		return c.Latest(ctx, params)
+	})
+}
+
+// Historical returns historical exchange rates for a specific date
+func (f *FailoverClient) Historical(ctx context.Context, params *HistoricalParams) (*HistoricalResponse, error) {
+	return failover(ctx, f, func(c Client) (*HistoricalResponse, error) {
+		return c.Historical(ctx, params)
+	})
+}
+
+// Convert converts an amount from one currency to another
+func (f *FailoverClient) Convert(ctx context.Context, params *ConvertParams) (*ConvertResponse, error) {
+	return failover(ctx, f, func(c Client) (*ConvertResponse, error) {
+		return c.Convert(ctx, params)
+	})
+}
@@ -0,0 +1,176 @@
+package currencyapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newProvider creates a stub provider that reports its name in latest responses
+func newProvider(name string, err error) *stubClient {
+	provider := newStubClient()
+	provider.latest.Meta.Provider = name
+	provider.err = err
+	return provider
+}
+
+func TestNewFailoverClientValidation(t *testing.T) {
+	if _, err := NewFailoverClient(nil); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for nil primary, got %v", err)
+	}
+	if _, err := NewFailoverClient(newStubClient(), nil); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for nil secondary, got %v", err)
+	}
+}
+
+func TestFailoverClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		primaryErr   error
+		secondaryErr error
+		wantProvider string
+		wantErr      func(error) bool
+		wantCooling  bool
+	}{
+		{
+			name:         "healthy primary",
+			wantProvider: "primary",
+		},
+		{
+			name:         "temporary error",
+			primaryErr:   &HTTPError{StatusCode: 503},
+			wantProvider: "secondary",
+			wantCooling:  true,
+		},
+		{
+			name:         "network error",
+			primaryErr:   &RequestError{Op: "execute_request", Err: errors.New("connection refused")},
+			wantProvider: "secondary",
+			wantCooling:  true,
+		},
+		{
+			name:         "quota exceeded",
+			primaryErr:   &APIError{StatusCode: 429, Code: "quota_exceeded"},
+			wantProvider: "secondary",
+			wantCooling:  true,
+		},
+		{
+			name:         "quota reserved",
+			primaryErr:   &QuotaReservedError{Remaining: 5, Reserve: 10},
+			wantProvider: "secondary",
+			wantCooling:  true,
+		},
+		{
+			name:       "permanent error",
+			primaryErr: &APIError{StatusCode: 401, Code: "invalid_api_key"},
+			wantErr:    IsAPIError,
+		},
+		{
+			name:         "all providers fail",
+			primaryErr:   &HTTPError{StatusCode: 503},
+			secondaryErr: &HTTPError{StatusCode: 502},
+			wantErr:      IsHTTPError,
+			wantCooling:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := newProvider("primary", tt.primaryErr)
+			secondary := newProvider("secondary", tt.secondaryErr)
+			client, err := NewFailoverClient(primary, secondary)
+			if err != nil {
+				t.Fatalf("NewFailoverClient() error = %v", err)
+			}
+
+			response, err := client.Latest(context.Background(), nil)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Latest() error = %v", err)
+				}
+				if response.Meta.Provider != tt.wantProvider {
+					t.Errorf("Served by %q, want %q", response.Meta.Provider, tt.wantProvider)
+				}
+			}
+
+			if cooling := client.CoolingDown(0); cooling != tt.wantCooling {
+				t.Errorf("CoolingDown(0) = %v, want %v", cooling, tt.wantCooling)
+			}
+			if !shouldFailover(tt.primaryErr) && secondary.count("latest") != 0 {
+				t.Error("Only temporary and quota errors may fail over")
+			}
+		})
+	}
+}
+
+func TestFailoverClientCooldown(t *testing.T) {
+	now := time.Date(2025, 12, 5, 12, 0, 0, 0, time.UTC)
+	primary := newProvider("primary", &HTTPError{StatusCode: 503})
+	secondary := newProvider("secondary", nil)
+	client, err := NewFailoverClient(primary, secondary)
+	if err != nil {
+		t.Fatalf("NewFailoverClient() error = %v", err)
+	}
+	client.now = func() time.Time { return now }
+	client.SetCooldown(30 * time.Second)
+	ctx := context.Background()
+
+	if _, err := client.Latest(ctx, nil); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+
+	// The primary is skipped while cooling down, even after it recovered
+	primary.setErr(nil)
+	now = now.Add(10 * time.Second)
+	response, err := client.Latest(ctx, nil)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if response.Meta.Provider != "secondary" || primary.count("latest") != 1 {
+		t.Errorf("Expected cooling down primary to be skipped, served by %q after %d primary calls",
+			response.Meta.Provider, primary.count("latest"))
+	}
+
+	// A cooling down provider is still used when every healthy provider fails
+	secondary.setErr(&HTTPError{StatusCode: 503})
+	response, err = client.Latest(ctx, nil)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if response.Meta.Provider != "primary" {
+		t.Errorf("Expected fallback to cooling down primary, served by %q", response.Meta.Provider)
+	}
+	if client.CoolingDown(0) || !client.CoolingDown(1) {
+		t.Error("Expected primary to recover and secondary to cool down")
+	}
+
+	// Once the cooldown expires the secondary is tried again
+	now = now.Add(time.Minute)
+	if client.CoolingDown(1) {
+		t.Error("Expected cooldown to expire")
+	}
+}
+
+func TestFailoverClientContextCanceled(t *testing.T) {
+	primary := newProvider("primary", &RequestError{Op: "execute_request", Err: context.Canceled})
+	secondary := newProvider("secondary", nil)
+	client, err := NewFailoverClient(primary, secondary)
+	if err != nil {
+		t.Fatalf("NewFailoverClient() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.Latest(ctx, nil); !IsRequestError(err) {
+		t.Errorf("Expected RequestError, got %v", err)
+	}
+	if secondary.count("latest") != 0 || client.CoolingDown(0) {
+		t.Error("A canceled request must neither fail over nor cool down the provider")
+	}
+}
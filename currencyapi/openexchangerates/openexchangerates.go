@@ -0,0 +1,389 @@
+// Package openexchangerates implements currencyapi.Client on top of the
+// Open Exchange Rates JSON API.
+// See: https://docs.openexchangerates.org/reference/api-introduction
+package openexchangerates
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+const (
+	// DefaultBaseURL is the default API endpoint
+	DefaultBaseURL = "https://openexchangerates.org/api/"
+	// DefaultTimeout is the default HTTP client timeout
+	DefaultTimeout = 10 * time.Second
+	// ProviderName identifies Open Exchange Rates in ResponseMeta.Provider
+	ProviderName = "openexchangerates"
+
+	// defaultBaseCurrency matches the CurrencyAPI default base currency
+	defaultBaseCurrency = "USD"
+)
+
+// Client is a currencyapi.Client backed by the Open Exchange Rates API.
+// Rates are always requested against the API's own base (USD on the free plan)
+// and rebased locally, so any base currency works on every plan. Conversions
+// are calculated locally as well.
+type Client struct {
+	appID      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option is a function that configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets a custom base URL for the API
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient creates a new Open Exchange Rates client with the provided App ID and options
+func NewClient(appID string, opts ...Option) (*Client, error) {
+	if appID == "" {
+		return nil, &currencyapi.ValidationError{Field: "appID", Message: "App ID is required"}
+	}
+
+	c := &Client{
+		appID:   appID,
+		baseURL: DefaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// ratesResponse is the response of the latest and historical endpoints
+type ratesResponse struct {
+	Timestamp int64                   `json:"timestamp"`
+	Base      string                  `json:"base"`
+	Rates     map[string]money.Amount `json:"rates"`
+}
+
+// usageResponse is the response of the usage endpoint
+type usageResponse struct {
+	Data struct {
+		Usage struct {
+			Requests          int `json:"requests"`
+			RequestsQuota     int `json:"requests_quota"`
+			RequestsRemaining int `json:"requests_remaining"`
+		} `json:"usage"`
+	} `json:"data"`
+}
+
+// errorResponse is the error body returned by the API
+type errorResponse struct {
+	Error       bool   `json:"error"`
+	Status      int    `json:"status"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+}
+
+// errorCodes maps API error messages to the CurrencyAPI error codes checked by callers
+var errorCodes = map[string]string{
+	"invalid_app_id": "invalid_api_key",
+	"missing_app_id": "invalid_api_key",
+	"not_allowed":    "invalid_api_key",
+}
+
+// doRequest performs an HTTP request and returns the response body or an error
+func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	reqURL, err := url.Parse(c.baseURL + endpoint)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "parse_url",
+			Err: err,
+		}
+	}
+
+	q := reqURL.Query()
+	for key, values := range params {
+		for _, value := range values {
+			q.Add(key, value)
+		}
+	}
+	q.Set("app_id", c.appID)
+	reqURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "create_request",
+			Err: err,
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "execute_request",
+			Err: err,
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &currencyapi.RequestError{
+			Op:  "read_response",
+			Err: err,
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, parseAPIError(resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// parseAPIError converts an error response to the currencyapi error types
+func parseAPIError(statusCode int, body []byte) error {
+	var apiErr errorResponse
+	if err := json.Unmarshal(body, &apiErr); err != nil || !apiErr.Error {
+		return &currencyapi.HTTPError{
+			StatusCode: statusCode,
+			Body:       string(body),
+		}
+	}
+
+	code, ok := errorCodes[apiErr.Message]
+	if !ok {
+		code = apiErr.Message
+	}
+	// access_restricted is an exhausted quota with a 429 and a feature the plan lacks
+	// with a 403, e.g. a base other than USD on the free plan, which waiting won't fix
+	if apiErr.Message == "access_restricted" && statusCode == http.StatusTooManyRequests {
+		code = "quota_exceeded"
+	}
+
+	return &currencyapi.APIError{
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    apiErr.Message,
+		Info:       apiErr.Description,
+	}
+}
+
+// fetchRates requests rates from the latest or historical endpoint. When currencies are
+// given, only those and the requested base are downloaded.
+func (c *Client) fetchRates(ctx context.Context, endpoint, base string, currencies []string) (*ratesResponse, error) {
+	params := url.Values{}
+	if len(currencies) > 0 {
+		params.Set("symbols", strings.Join(append([]string{base}, currencies...), ","))
+	}
+
+	body, err := c.doRequest(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ratesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, &currencyapi.ParseError{
+			Endpoint: endpoint,
+			Err:      err,
+		}
+	}
+	return &response, nil
+}
+
+// rebase converts the rates of a response to the requested base currency
+func (r *ratesResponse) rebase(base string, currencies []string) (map[string]currencyapi.RateInfo, error) {
+	rates := make(map[string]currencyapi.RateInfo, len(r.Rates))
+	for code, value := range r.Rates {
+		rates[code] = currencyapi.RateInfo{Code: code, Value: value}
+	}
+	return currencyapi.CrossRates(rates, r.Base, base, currencies)
+}
+
+// lastUpdatedAt formats the response timestamp like CurrencyAPI does
+func (r *ratesResponse) lastUpdatedAt() string {
+	return time.Unix(r.Timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+// Status returns the monthly quota of the App ID
+func (c *Client) Status(ctx context.Context) (*currencyapi.StatusResponse, error) {
+	body, err := c.doRequest(ctx, "usage.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage usageResponse
+	if err := json.Unmarshal(body, &usage); err != nil {
+		return nil, &currencyapi.ParseError{
+			Endpoint: "usage.json",
+			Err:      err,
+		}
+	}
+
+	var response currencyapi.StatusResponse
+	response.Quotas.Month.Total = usage.Data.Usage.RequestsQuota
+	response.Quotas.Month.Used = usage.Data.Usage.Requests
+	response.Quotas.Month.Remaining = usage.Data.Usage.RequestsRemaining
+	return &response, nil
+}
+
+// Currencies returns available currencies
+func (c *Client) Currencies(ctx context.Context, params *currencyapi.CurrenciesParams) (*currencyapi.CurrenciesResponse, error) {
+	body, err := c.doRequest(ctx, "currencies.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var names map[string]string
+	if err := json.Unmarshal(body, &names); err != nil {
+		return nil, &currencyapi.ParseError{
+			Endpoint: "currencies.json",
+			Err:      err,
+		}
+	}
+
+	response := &currencyapi.CurrenciesResponse{Data: make(map[string]currencyapi.CurrencyInfo, len(names))}
+	if params != nil && params.Type != "" && params.Type != "fiat" {
+		return response, nil
+	}
+	for code, name := range names {
+		if params != nil && len(params.Currencies) > 0 && !slices.Contains(params.Currencies, code) {
+			continue
+		}
+		response.Data[code] = currencyapi.CurrencyInfo{
+			Code:          code,
+			Name:          name,
+			NamePlural:    name,
+			Symbol:        code,
+			SymbolNative:  code,
+			DecimalDigits: 2,
+			Type:          "fiat",
+		}
+	}
+	return response, nil
+}
+
+// Latest returns the latest exchange rates
+func (c *Client) Latest(ctx context.Context, params *currencyapi.LatestParams) (*currencyapi.LatestResponse, error) {
+	base := defaultBaseCurrency
+	var currencies []string
+	if params != nil {
+		if params.BaseCurrency != "" {
+			base = params.BaseCurrency
+		}
+		currencies = params.Currencies
+	}
+
+	latest, err := c.fetchRates(ctx, "latest.json", base, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := latest.rebase(base, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.LatestResponse{Data: rates}
+	response.Meta.LastUpdatedAt = latest.lastUpdatedAt()
+	response.Meta.Provider = ProviderName
+	return response, nil
+}
+
+// Historical returns historical exchange rates for a specific date
+func (c *Client) Historical(ctx context.Context, params *currencyapi.HistoricalParams) (*currencyapi.HistoricalResponse, error) {
+	if params == nil || params.Date == "" {
+		return nil, &currencyapi.ValidationError{
+			Field:   "date",
+			Message: "date parameter is required for historical endpoint",
+		}
+	}
+	if _, err := time.Parse("2006-01-02", params.Date); err != nil {
+		return nil, &currencyapi.ValidationError{
+			Field:   "date",
+			Message: "date must be in format YYYY-MM-DD",
+		}
+	}
+
+	base := params.BaseCurrency
+	if base == "" {
+		base = defaultBaseCurrency
+	}
+
+	historical, err := c.fetchRates(ctx, "historical/"+params.Date+".json", base, params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := historical.rebase(base, params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &currencyapi.HistoricalResponse{Data: rates}
+	response.Meta.LastUpdatedAt = historical.lastUpdatedAt()
+	response.Meta.Provider = ProviderName
+	return response, nil
+}
+
+// Convert converts an amount using the latest or historical rates
+func (c *Client) Convert(ctx context.Context, params *currencyapi.ConvertParams) (*currencyapi.ConvertResponse, error) {
+	if params == nil {
+		return nil, &currencyapi.ValidationError{
+			Field:   "params",
+			Message: "convert parameters are required",
+		}
+	}
+
+	var rates map[string]currencyapi.RateInfo
+	var lastUpdatedAt string
+	if params.Date != "" {
+		historical, err := c.Historical(ctx, &currencyapi.HistoricalParams{
+			Date:         params.Date,
+			BaseCurrency: params.BaseCurrency,
+			Currencies:   params.Currencies,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rates, lastUpdatedAt = historical.Data, historical.Meta.LastUpdatedAt
+	} else {
+		latest, err := c.Latest(ctx, &currencyapi.LatestParams{
+			BaseCurrency: params.BaseCurrency,
+			Currencies:   params.Currencies,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rates, lastUpdatedAt = latest.Data, latest.Meta.LastUpdatedAt
+	}
+
+	response := &currencyapi.ConvertResponse{Data: make(map[string]currencyapi.ConvertRateInfo, len(rates))}
+	response.Meta.LastUpdatedAt = lastUpdatedAt
+	response.Meta.Provider = ProviderName
+	for code, rate := range rates {
+		response.Data[code] = currencyapi.ConvertRateInfo{Code: code, Value: rate.Value.Mul(params.Value)}
+	}
+	return response, nil
+}
@@ -0,0 +1,272 @@
+package openexchangerates
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+var _ currencyapi.Client = (*Client)(nil)
+
+// newTestClient serves the recorded responses in testdata and records request queries
+func newTestClient(t *testing.T) (*Client, func() []url.Values) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("app_id") != "test" {
+			serveFixture(w, http.StatusUnauthorized, "invalid_app_id.json")
+			return
+		}
+		serveFixture(w, http.StatusOK, r.URL.Path[1:])
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("test", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	return client, func() []url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]url.Values(nil), queries...)
+	}
+}
+
+// serveFixture writes a testdata file, or the API not_found error when it does not exist
+func serveFixture(w http.ResponseWriter, statusCode int, name string) {
+	data, err := os.ReadFile(filepath.Join("testdata", filepath.FromSlash(name)))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":true,"status":404,"message":"not_found","description":"Client requested a non-existent resource/route."}`))
+		return
+	}
+	w.WriteHeader(statusCode)
+	w.Write(data)
+}
+
+func assertRate(t *testing.T, rates map[string]currencyapi.RateInfo, code string, want float64) {
+	t.Helper()
+	rate, ok := rates[code]
+	if !ok {
+		t.Errorf("Missing rate for %s", code)
+		return
+	}
+	if rate.Code != code || math.Abs(rate.Value.Float64()-want) > 1e-9 {
+		t.Errorf("Rate for %s = %+v, want %v", code, rate, want)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient(""); !currencyapi.IsValidationError(err) {
+		t.Errorf("Expected ValidationError for empty App ID, got %v", err)
+	}
+}
+
+func TestClientLatest(t *testing.T) {
+	client, queries := newTestClient(t)
+	ctx := context.Background()
+
+	// USD based rates are returned as published
+	response, err := client.Latest(ctx, nil)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if len(response.Data) != 6 {
+		t.Errorf("Expected 6 rates, got %d", len(response.Data))
+	}
+	assertRate(t, response.Data, "USD", 1)
+	assertRate(t, response.Data, "EUR", 0.858)
+	if response.Meta.LastUpdatedAt != "2025-12-05T23:00:00Z" {
+		t.Errorf("Unexpected last updated timestamp: %q", response.Meta.LastUpdatedAt)
+	}
+
+	// Other base currencies are rebased locally
+	response, err = client.Latest(ctx, &currencyapi.LatestParams{
+		BaseCurrency: "EUR",
+		Currencies:   []string{"GBP", "UAH"},
+	})
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if len(response.Data) != 2 {
+		t.Errorf("Expected 2 rates, got %d", len(response.Data))
+	}
+	assertRate(t, response.Data, "GBP", 0.75/0.858)
+	assertRate(t, response.Data, "UAH", 41.9/0.858)
+
+	got := queries()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	if symbols := got[0].Get("symbols"); symbols != "" {
+		t.Errorf("Expected no symbols filter, got %q", symbols)
+	}
+	if symbols := got[1].Get("symbols"); symbols != "EUR,GBP,UAH" {
+		t.Errorf("Expected symbols EUR,GBP,UAH, got %q", symbols)
+	}
+	if base := got[1].Get("base"); base != "" {
+		t.Errorf("Base must not be sent to the API, got %q", base)
+	}
+}
+
+func TestClientHistorical(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	response, err := client.Historical(ctx, &currencyapi.HistoricalParams{
+		Date:         "2025-01-02",
+		BaseCurrency: "GBP",
+		Currencies:   []string{"PLN"},
+	})
+	if err != nil {
+		t.Fatalf("Historical() error = %v", err)
+	}
+	assertRate(t, response.Data, "PLN", 4.135/0.8038)
+	if response.Meta.LastUpdatedAt != "2025-01-02T23:59:59Z" {
+		t.Errorf("Unexpected last updated timestamp: %q", response.Meta.LastUpdatedAt)
+	}
+
+	tests := []struct {
+		name   string
+		params *currencyapi.HistoricalParams
+	}{
+		{name: "missing params", params: nil},
+		{name: "malformed date", params: &currencyapi.HistoricalParams{Date: "02.01.2025"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Historical(ctx, tt.params); !currencyapi.IsValidationError(err) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+
+	_, err = client.Historical(ctx, &currencyapi.HistoricalParams{Date: "2019-01-01"})
+	var apiErr *currencyapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "not_found" {
+		t.Errorf("Expected not_found APIError for unrecorded date, got %v", err)
+	}
+}
+
+func TestClientConvert(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	response, err := client.Convert(context.Background(), &currencyapi.ConvertParams{
+		BaseCurrency: "EUR",
+		Currencies:   []string{"USD"},
+		Value:        money.MustParse("85.8"),
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if got := response.Data["USD"].Value.Float64(); math.Abs(got-100) > 1e-9 {
+		t.Errorf("Converted USD = %v, want 100", got)
+	}
+}
+
+func TestClientCurrenciesAndStatus(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	currencies, err := client.Currencies(ctx, &currencyapi.CurrenciesParams{Currencies: []string{"UAH", "PLN"}})
+	if err != nil {
+		t.Fatalf("Currencies() error = %v", err)
+	}
+	if len(currencies.Data) != 2 || currencies.Data["UAH"].Name != "Ukrainian Hryvnia" {
+		t.Errorf("Unexpected currencies: %+v", currencies.Data)
+	}
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if month := status.Quotas.Month; month.Total != 1000 || month.Used != 250 || month.Remaining != 750 {
+		t.Errorf("Unexpected monthly quota: %+v", month)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixture   string
+		status    int
+		check     func(error) bool
+		wantError string
+	}{
+		{
+			name:      "invalid app id",
+			fixture:   "invalid_app_id.json",
+			status:    http.StatusUnauthorized,
+			check:     func(err error) bool { return errors.As(err, new(*currencyapi.APIError)) },
+			wantError: "invalid_api_key",
+		},
+		{
+			name:      "access restricted",
+			fixture:   "access_restricted.json",
+			status:    http.StatusTooManyRequests,
+			check:     func(err error) bool { return errors.As(err, new(*currencyapi.APIError)) },
+			wantError: "quota_exceeded",
+		},
+		{
+			name:      "plan restriction",
+			fixture:   "access_restricted_plan.json",
+			status:    http.StatusForbidden,
+			check:     func(err error) bool { return !currencyapi.IsQuotaExceededError(err) },
+			wantError: "access_restricted",
+		},
+		{
+			name:   "non json error",
+			status: http.StatusBadGateway,
+			check:  currencyapi.IsTemporaryError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.fixture == "" {
+					w.WriteHeader(tt.status)
+					w.Write([]byte("Bad Gateway"))
+					return
+				}
+				serveFixture(w, tt.status, tt.fixture)
+			}))
+			defer server.Close()
+
+			client, err := NewClient("test", WithBaseURL(server.URL+"/"))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			_, err = client.Latest(context.Background(), nil)
+			if !tt.check(err) {
+				t.Fatalf("Unexpected error type: %v", err)
+			}
+			if tt.wantError != "" {
+				var apiErr *currencyapi.APIError
+				errors.As(err, &apiErr)
+				if apiErr.Code != tt.wantError {
+					t.Errorf("Expected code %q, got %q", tt.wantError, apiErr.Code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package currencyapi
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultQuotaRefreshInterval is how often the quota guard re-reads the API status by default
+const DefaultQuotaRefreshInterval = 5 * time.Minute
+
+type essentialKey struct{}
+
+// WithEssential marks requests made with the returned context as essential.
+// Essential requests are allowed to spend the quota reserved by a QuotaGuard.
+func WithEssential(ctx context.Context) context.Context {
+	return context.WithValue(ctx, essentialKey{}, true)
+}
+
+// IsEssential reports whether the context was marked with WithEssential
+func IsEssential(ctx context.Context) bool {
+	essential, _ := ctx.Value(essentialKey{}).(bool)
+	return essential
+}
+
+// QuotaGuardOptions configures a QuotaGuard
+type QuotaGuardOptions struct {
+	// Reserve is the number of monthly requests kept for essential calls
+	Reserve int
+	// RefreshInterval is how often the remaining quota is read from the status endpoint (default: 5 minutes)
+	RefreshInterval time.Duration
+}
+
+// QuotaGuard is a Client decorator that protects the monthly quota. Once the remaining
+// number of requests drops to the reserve, non-essential calls fail with a QuotaReservedError.
+type QuotaGuard struct {
+	inner     Client
+	opts      QuotaGuardOptions
+	now       func() time.Time
+	remaining int
+	known     bool
+	checkedAt time.Time
+	mu        sync.Mutex
+	refreshMu sync.Mutex
+}
+
+// NewQuotaGuard wraps a client with a monthly quota guard
+func NewQuotaGuard(inner Client, opts QuotaGuardOptions) (Client, error) {
+	if inner == nil {
+		return nil, &ValidationError{Field: "inner", Message: "client is required"}
+	}
+	if opts.Reserve < 0 {
+		return nil, &ValidationError{Field: "reserve", Message: "reserve must not be negative"}
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultQuotaRefreshInterval
+	}
+
+	return &QuotaGuard{
+		inner: inner,
+		opts:  opts,
+		now:   time.Now,
+	}, nil
+}
+
+// Remaining returns the last known number of remaining monthly requests
+func (g *QuotaGuard) Remaining() (int, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.remaining, g.known
+}
+
+// stale reports whether the quota needs to be re-read from the API
+func (g *QuotaGuard) stale() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.checkedAt.IsZero() || g.now().Sub(g.checkedAt) >= g.opts.RefreshInterval
+}
+
+// refresh re-reads the remaining quota, unless another call has just done so
+func (g *QuotaGuard) refresh(ctx context.Context) {
+	g.refreshMu.Lock()
+	defer g.refreshMu.Unlock()
+
+	if !g.stale() {
+		return
+	}
+
+	status, err := g.inner.Status(ctx)
+	if err != nil {
+		// Fail open: an unreadable status must not block requests
+		log.Printf("Quota guard: failed to refresh quota: %v", err)
+		return
+	}
+	g.update(status)
+}
+
+// update records the quota reported by a status response.
+// Providers without a monthly quota report a zero total and are never limited.
+func (g *QuotaGuard) update(status *StatusResponse) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remaining = status.Quotas.Month.Remaining
+	g.known = status.Quotas.Month.Total > 0
+	g.checkedAt = g.now()
+}
+
+// admit checks whether a request may spend quota
+func (g *QuotaGuard) admit(ctx context.Context) error {
+	if IsEssential(ctx) {
+		return nil
+	}
+
+	if g.stale() {
+		g.refresh(ctx)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.known && g.remaining <= g.opts.Reserve {
+		return &QuotaReservedError{
+			Remaining: g.remaining,
+			Reserve:   g.opts.Reserve,
+		}
+	}
+	return nil
+}
+
+// spent accounts for a successful request until the next refresh
+func (g *QuotaGuard) spent() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.known && g.remaining > 0 {
+		g.remaining--
+	}
+}
+
+// Status returns the current API status and refreshes the known quota
+func (g *QuotaGuard) Status(ctx context.Context) (*StatusResponse, error) {
+	status, err := g.inner.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.update(status)
+	return status, nil
+}
+
+// Currencies returns available currencies
+func (g *QuotaGuard) Currencies(ctx context.Context, params *CurrenciesParams) (*CurrenciesResponse, error) {
+	if err := g.admit(ctx); err != nil {
+		return nil, err
+	}
+	response, err := g.inner.Currencies(ctx, params)
+	if err == nil {
+		g.spent()
+	}
+	return response, err
+}
+
+// Latest returns the latest exchange rates
+func (g *QuotaGuard) Latest(ctx context.Context, params *LatestParams) (*LatestResponse, error) {
+	if err := g.admit(ctx); err != nil {
+		return nil, err
+	}
+	response, err := g.inner.Latest(ctx, params)
+	if err == nil {
+		g.spent()
+	}
+	return response, err
+}
+
+// Historical returns historical exchange rates for a specific date
+func (g *QuotaGuard) Historical(ctx context.Context, params *HistoricalParams) (*HistoricalResponse, error) {
+	if err := g.admit(ctx); err != nil {
+		return nil, err
+	}
+	response, err := g.inner.Historical(ctx, params)
+	if err == nil {
+		g.spent()
+	}
+	return response, err
+}
+
+// Convert converts an amount from one currency to another
+func (g *QuotaGuard) Convert(ctx context.Context, params *ConvertParams) (*ConvertResponse, error) {
+	if err := g.admit(ctx); err != nil {
+		return nil, err
+	}
+	response, err := g.inner.Convert(ctx, params)
+	if err == nil {
+		g.spent()
+	}
+	return response, err
+}
@@ -0,0 +1,126 @@
+package currencyapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuotaGuard(t *testing.T) {
+	stub := newStubClient()
+	stub.status.Quotas.Month.Total = 300
+	stub.status.Quotas.Month.Remaining = 12
+
+	client, err := NewQuotaGuard(stub, QuotaGuardOptions{Reserve: 10, RefreshInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewQuotaGuard() error = %v", err)
+	}
+	guard := client.(*QuotaGuard)
+
+	ctx := context.Background()
+
+	// Two requests are available above the reserve
+	for i := 0; i < 2; i++ {
+		if _, err := client.Latest(ctx, nil); err != nil {
+			t.Fatalf("Latest() #%d error = %v", i+1, err)
+		}
+	}
+	if remaining, _ := guard.Remaining(); remaining != 10 {
+		t.Errorf("Remaining() = %d, want 10", remaining)
+	}
+
+	// The reserve is refused to non-essential calls
+	_, err = client.Latest(ctx, nil)
+	var reservedErr *QuotaReservedError
+	if !errors.As(err, &reservedErr) {
+		t.Fatalf("Expected QuotaReservedError, got %v", err)
+	}
+	if reservedErr.Remaining != 10 || reservedErr.Reserve != 10 {
+		t.Errorf("Unexpected QuotaReservedError: %+v", reservedErr)
+	}
+	if !IsQuotaReservedError(err) {
+		t.Error("Expected IsQuotaReservedError() to return true")
+	}
+
+	// Essential calls may spend the reserve
+	if _, err := client.Latest(WithEssential(ctx), nil); err != nil {
+		t.Errorf("Essential Latest() error = %v", err)
+	}
+
+	if got := stub.count("latest"); got != 3 {
+		t.Errorf("Expected 3 upstream calls, got %d", got)
+	}
+	if got := stub.count("status"); got != 1 {
+		t.Errorf("Expected the quota to be read once, got %d", got)
+	}
+}
+
+func TestQuotaGuardRefresh(t *testing.T) {
+	stub := newStubClient()
+	stub.status.Quotas.Month.Total = 300
+	stub.status.Quotas.Month.Remaining = 5
+
+	client, _ := NewQuotaGuard(stub, QuotaGuardOptions{Reserve: 5, RefreshInterval: time.Minute})
+	guard := client.(*QuotaGuard)
+	now := time.Date(2025, 12, 5, 12, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err := client.Currencies(ctx, nil); !IsQuotaReservedError(err) {
+		t.Fatalf("Expected QuotaReservedError, got %v", err)
+	}
+
+	// The quota is re-read once the refresh interval passes
+	stub.mu.Lock()
+	stub.status.Quotas.Month.Remaining = 500
+	stub.mu.Unlock()
+	now = now.Add(time.Minute)
+
+	if _, err := client.Currencies(ctx, nil); err != nil {
+		t.Errorf("Currencies() after refresh error = %v", err)
+	}
+	if got := stub.count("status"); got != 2 {
+		t.Errorf("Expected 2 status calls, got %d", got)
+	}
+}
+
+func TestQuotaGuardFailsOpen(t *testing.T) {
+	stub := newStubClient()
+	stub.err = &HTTPError{StatusCode: 500}
+
+	client, _ := NewQuotaGuard(stub, QuotaGuardOptions{Reserve: 100})
+
+	_, err := client.Latest(context.Background(), nil)
+	if IsQuotaReservedError(err) {
+		t.Fatal("Guard must not refuse calls when the quota is unknown")
+	}
+	if !IsHTTPError(err) {
+		t.Errorf("Expected upstream HTTPError, got %v", err)
+	}
+}
+
+func TestIsEssential(t *testing.T) {
+	ctx := context.Background()
+	if IsEssential(ctx) {
+		t.Error("Plain context must not be essential")
+	}
+	if !IsEssential(WithEssential(ctx)) {
+		t.Error("Expected context to be essential")
+	}
+}
+
+func TestQuotaGuardWithoutQuota(t *testing.T) {
+	// Providers without a monthly quota report zero totals
+	stub := newStubClient()
+
+	client, _ := NewQuotaGuard(stub, QuotaGuardOptions{Reserve: 10, RefreshInterval: time.Hour})
+	for i := 0; i < 3; i++ {
+		if _, err := client.Latest(context.Background(), nil); err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+	}
+	if got := stub.count("status"); got != 1 {
+		t.Errorf("Expected the quota to be read once, got %d", got)
+	}
+}
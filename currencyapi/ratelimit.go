@@ -0,0 +1,146 @@
+package currencyapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit describes a token bucket: Requests tokens are added every Per interval
+// and at most Burst tokens can be accumulated
+type RateLimit struct {
+	Requests int           // Number of requests allowed per interval
+	Per      time.Duration // Length of the interval (default: 1 second)
+	Burst    int           // Maximum number of requests sent at once (default: 1)
+}
+
+// RateLimitedClient is a Client decorator that limits how fast requests reach the inner client.
+// Status requests are not limited because they don't count against the API quota.
+type RateLimitedClient struct {
+	inner  Client
+	bucket *tokenBucket
+}
+
+// NewRateLimitedClient wraps a client with a client-side token bucket rate limiter
+func NewRateLimitedClient(inner Client, limit RateLimit) (Client, error) {
+	if inner == nil {
+		return nil, &ValidationError{Field: "inner", Message: "client is required"}
+	}
+	if limit.Requests <= 0 {
+		return nil, &ValidationError{Field: "requests", Message: "rate limit must allow at least one request"}
+	}
+	if limit.Per <= 0 {
+		limit.Per = time.Second
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+
+	return &RateLimitedClient{
+		inner:  inner,
+		bucket: newTokenBucket(limit, time.Now),
+	}, nil
+}
+
+// wait blocks until the rate limiter admits a request
+func (c *RateLimitedClient) wait(ctx context.Context) error {
+	if err := c.bucket.Wait(ctx); err != nil {
+		return &RequestError{
+			Op:  "rate_limit",
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// Status returns the current API status
+func (c *RateLimitedClient) Status(ctx context.Context) (*StatusResponse, error) {
+	return c.inner.Status(ctx)
+}
+
+// Currencies returns available currencies
+func (c *RateLimitedClient) Currencies(ctx context.Context, params *CurrenciesParams) (*CurrenciesResponse, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.Currencies(ctx, params)
+}
+
+// Latest returns the latest exchange rates
+func (c *RateLimitedClient) Latest(ctx context.Context, params *LatestParams) (*LatestResponse, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.Latest(ctx, params)
+}
+
+// Historical returns historical exchange rates for a specific date
+func (c *RateLimitedClient) Historical(ctx context.Context, params *HistoricalParams) (*HistoricalResponse, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.Historical(ctx, params)
+}
+
+// Convert converts an amount from one currency to another
+func (c *RateLimitedClient) Convert(ctx context.Context, params *ConvertParams) (*ConvertResponse, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.Convert(ctx, params)
+}
+
+// tokenBucket is a thread-safe token bucket rate limiter
+type tokenBucket struct {
+	interval time.Duration // Time needed to add a single token
+	burst    float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// newTokenBucket creates a full token bucket for the given rate limit
+func newTokenBucket(limit RateLimit, now func() time.Time) *tokenBucket {
+	return &tokenBucket{
+		interval: limit.Per / time.Duration(limit.Requests),
+		burst:    float64(limit.Burst),
+		tokens:   float64(limit.Burst),
+		last:     now(),
+		now:      now,
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long to wait for one
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(b.interval)
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) * float64(b.interval))
+}
+
+// Wait blocks until a token is available or the context is done
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+		if err := wait(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package currencyapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimitedClientValidation(t *testing.T) {
+	if _, err := NewRateLimitedClient(nil, RateLimit{Requests: 1}); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for nil client, got %v", err)
+	}
+	if _, err := NewRateLimitedClient(newStubClient(), RateLimit{}); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for zero requests, got %v", err)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2025, 12, 5, 12, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(RateLimit{Requests: 2, Per: time.Second, Burst: 2}, func() time.Time { return now })
+
+	// The bucket starts full
+	if d := bucket.reserve(); d != 0 {
+		t.Errorf("First reserve() = %v, want 0", d)
+	}
+	if d := bucket.reserve(); d != 0 {
+		t.Errorf("Second reserve() = %v, want 0", d)
+	}
+
+	// The third request has to wait for a token to be added
+	if d := bucket.reserve(); d != 500*time.Millisecond {
+		t.Errorf("Third reserve() = %v, want 500ms", d)
+	}
+
+	// Tokens never accumulate beyond the burst size
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if d := bucket.reserve(); d != 0 {
+			t.Errorf("reserve() after refill = %v, want 0", d)
+		}
+	}
+	if d := bucket.reserve(); d == 0 {
+		t.Error("Expected reserve() to wait once the burst is spent")
+	}
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	stub := newStubClient()
+	client, err := NewRateLimitedClient(stub, RateLimit{Requests: 1, Per: 50 * time.Millisecond, Burst: 1})
+	if err != nil {
+		t.Fatalf("NewRateLimitedClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Latest(ctx, nil); err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to be spaced out, took %v", elapsed)
+	}
+
+	// Status requests don't count against the quota and are never limited
+	for i := 0; i < 5; i++ {
+		if _, err := client.Status(ctx); err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+	}
+}
+
+func TestRateLimitedClientContextCancellation(t *testing.T) {
+	client, _ := NewRateLimitedClient(newStubClient(), RateLimit{Requests: 1, Per: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Latest(ctx, nil); err != nil {
+		t.Fatalf("First Latest() error = %v", err)
+	}
+
+	_, err := client.Latest(ctx, nil)
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.Op != "rate_limit" {
+		t.Fatalf("Expected rate_limit RequestError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package currencyapi
+
+import "github.com/BohdanKyryliuk/golang/money"
+
+// crossRatePlaces is the number of decimal places kept by rebased rates
+const crossRatePlaces = 18
+
+// CrossRates rebases exchange rates quoted against one currency to another base currency.
+// The rates map holds the value of one unit of from in every other currency; from itself
+// may be omitted. If currencies is empty, all known currencies are returned.
+func CrossRates(rates map[string]RateInfo, from, to string, currencies []string) (map[string]RateInfo, error) {
+	quote := func(code string) (money.Amount, bool) {
+		if code == from {
+			return money.New(1, 0), true
+		}
+		rate, ok := rates[code]
+		return rate.Value, ok && rate.Value.Sign() > 0
+	}
+
+	baseValue, ok := quote(to)
+	if !ok {
+		return nil, &ValidationError{
+			Field:   "base_currency",
+			Message: "no rate available for " + to,
+		}
+	}
+
+	codes := currencies
+	if len(codes) == 0 {
+		codes = make([]string, 0, len(rates)+1)
+		codes = append(codes, from)
+		for code := range rates {
+			if code != from {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	result := make(map[string]RateInfo, len(codes))
+	for _, code := range codes {
+		value, ok := quote(code)
+		if !ok {
+			return nil, &ValidationError{
+				Field:   "currencies",
+				Message: "no rate available for " + code,
+			}
+		}
+		if to != from {
+			// baseValue is positive, so the division cannot fail
+			value, _ = value.Div(baseValue, crossRatePlaces, money.HalfEven)
+			value = value.Normalize()
+		}
+		result[code] = RateInfo{Code: code, Value: value}
+	}
+	return result, nil
+}
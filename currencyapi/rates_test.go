@@ -0,0 +1,102 @@
+package currencyapi
+
+import (
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+func TestCrossRates(t *testing.T) {
+	usdRates := map[string]RateInfo{
+		"EUR": {Code: "EUR", Value: money.MustParse("0.8")},
+		"GBP": {Code: "GBP", Value: money.MustParse("0.5")},
+		"UAH": {Code: "UAH", Value: money.MustParse("40")},
+	}
+
+	tests := []struct {
+		name       string
+		to         string
+		currencies []string
+		want       map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "same base",
+			to:   "USD",
+			want: map[string]string{"USD": "1", "EUR": "0.8", "GBP": "0.5", "UAH": "40"},
+		},
+		{
+			name: "rebase to EUR",
+			to:   "EUR",
+			want: map[string]string{"USD": "1.25", "EUR": "1", "GBP": "0.625", "UAH": "50"},
+		},
+		{
+			name:       "filtered currencies",
+			to:         "GBP",
+			currencies: []string{"USD", "UAH"},
+			want:       map[string]string{"USD": "2", "UAH": "80"},
+		},
+		{
+			name:    "unknown base",
+			to:      "JPY",
+			wantErr: true,
+		},
+		{
+			name:       "unknown quote",
+			to:         "EUR",
+			currencies: []string{"JPY"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CrossRates(usdRates, "USD", tt.to, tt.currencies)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CrossRates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsValidationError(err) {
+					t.Errorf("Expected ValidationError, got %T", err)
+				}
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("CrossRates() returned %d rates, want %d", len(got), len(tt.want))
+			}
+			for code, want := range tt.want {
+				rate, ok := got[code]
+				if !ok {
+					t.Errorf("Missing rate for %s", code)
+					continue
+				}
+				if rate.Code != code || rate.Value.String() != want {
+					t.Errorf("Rate for %s = %+v, want %v", code, rate, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCrossRatesPrecision(t *testing.T) {
+	rates := map[string]RateInfo{
+		"JPY": {Code: "JPY", Value: money.MustParse("150")},
+		"BTC": {Code: "BTC", Value: money.MustParse("0.0000105")},
+	}
+
+	got, err := CrossRates(rates, "USD", "JPY", nil)
+	if err != nil {
+		t.Fatalf("CrossRates() error = %v", err)
+	}
+	want := map[string]string{
+		"USD": "0.006666666666666667",
+		"JPY": "1",
+		"BTC": "0.00000007",
+	}
+	for code, value := range want {
+		if got[code].Value.String() != value {
+			t.Errorf("Rate for %s = %s, want %s", code, got[code].Value, value)
+		}
+	}
+}
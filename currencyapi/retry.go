@@ -0,0 +1,130 @@
+package currencyapi
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of temporary failures.
+// Retries are attempted only for errors reported by IsTemporaryError,
+// except for exhausted quotas which will not recover by retrying.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one (default: 3)
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry (default: 500ms)
+	InitialBackoff time.Duration
+	// MaxBackoff caps a single delay, jitter included (default: 10s). Retries stop when
+	// Retry-After asks for a longer delay, so that the error reaches the caller instead.
+	MaxBackoff time.Duration
+	// Multiplier is the growth factor applied to the delay after each attempt (default: 2)
+	Multiplier float64
+	// Jitter is the fraction of the delay that is randomized, between 0 and 1; 0 disables
+	// jitter and values outside that range get the default (0.2)
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a retry policy with sensible defaults
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy enables automatic retries with exponential backoff.
+// Zero or invalid fields of the policy are replaced with the values from DefaultRetryPolicy,
+// except Jitter: 0 disables jitter and only values outside [0, 1] get the default.
+func WithRetryPolicy(policy RetryPolicy) HttpApiClientOption {
+	return func(c *HttpApiClient) {
+		defaults := DefaultRetryPolicy()
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = defaults.MaxAttempts
+		}
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = defaults.InitialBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = defaults.MaxBackoff
+		}
+		if policy.Multiplier < 1 {
+			policy.Multiplier = defaults.Multiplier
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			policy.Jitter = defaults.Jitter
+		}
+		c.retryPolicy = &policy
+	}
+}
+
+// shouldRetry reports whether a failed attempt is worth repeating
+func (p *RetryPolicy) shouldRetry(err error) bool {
+	return IsTemporaryError(err) && !IsQuotaExceededError(err)
+}
+
+// backoff returns the delay before the given retry (1 for the first retry)
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := min(float64(p.InitialBackoff)*math.Pow(p.Multiplier, float64(retry-1)), float64(p.MaxBackoff))
+	if p.Jitter > 0 {
+		// Spread the delay uniformly over [delay*(1-jitter), delay*(1+jitter)]
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(min(delay, float64(p.MaxBackoff)))
+}
+
+// nextDelay returns how long to wait before the given retry and whether the retry
+// should happen at all. A Retry-After hint takes precedence over the computed backoff,
+// and no retry is scheduled when the hint exceeds MaxBackoff or the wait would end past
+// the context deadline.
+func (p *RetryPolicy) nextDelay(ctx context.Context, retry int, err error) (time.Duration, bool) {
+	delay := p.backoff(retry)
+	if retryAfter, ok := GetRetryAfter(err); ok {
+		if retryAfter > p.MaxBackoff {
+			return 0, false
+		}
+		delay = retryAfter
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return 0, false
+	}
+	return delay, true
+}
+
+// wait blocks for the given delay or until the context is done
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
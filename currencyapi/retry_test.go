@@ -0,0 +1,257 @@
+package currencyapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy keeps retry tests quick and deterministic
+func fastRetryPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestClient_RetriesTemporaryErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("upstream unavailable"))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"EUR": map[string]interface{}{"code": "EUR", "value": 0.85},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpApiClient("test-key",
+		WithBaseURL(server.URL+"/"),
+		WithRetryPolicy(fastRetryPolicy(3)),
+	)
+
+	response, err := client.Latest(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if len(response.Data) != 1 {
+		t.Errorf("Expected 1 currency, got %d", len(response.Data))
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestClient_RetryStopsAtMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, _ := NewHttpApiClient("test-key",
+		WithBaseURL(server.URL+"/"),
+		WithRetryPolicy(fastRetryPolicy(4)),
+	)
+
+	_, err := client.Latest(context.Background(), nil)
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected HTTPError 502, got %v", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("Expected 4 attempts, got %d", got)
+	}
+}
+
+func TestClient_NoRetryForPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		code       string
+	}{
+		{"invalid API key", http.StatusUnauthorized, "invalid_api_key"},
+		{"quota exceeded", http.StatusTooManyRequests, "quota_exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.statusCode)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": map[string]string{"code": tt.code, "message": tt.name},
+				})
+			}))
+			defer server.Close()
+
+			client, _ := NewHttpApiClient("test-key",
+				WithBaseURL(server.URL+"/"),
+				WithRetryPolicy(fastRetryPolicy(3)),
+			)
+
+			if _, err := client.Latest(context.Background(), nil); !IsAPIError(err) {
+				t.Fatalf("Expected APIError, got %v", err)
+			}
+			if got := calls.Load(); got != 1 {
+				t.Errorf("Expected 1 attempt, got %d", got)
+			}
+		})
+	}
+}
+
+func TestClient_RetryHonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var firstCall time.Time
+	var retryDelay time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			firstCall = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		retryDelay = time.Since(firstCall)
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	}))
+	defer server.Close()
+
+	policy := fastRetryPolicy(2)
+	policy.MaxBackoff = 2 * time.Second
+	client, _ := NewHttpApiClient("test-key", WithBaseURL(server.URL+"/"), WithRetryPolicy(policy))
+
+	if _, err := client.Latest(context.Background(), nil); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if retryDelay < time.Second {
+		t.Errorf("Expected retry after at least 1s, got %v", retryDelay)
+	}
+}
+
+func TestClient_RetryRespectsContextDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := fastRetryPolicy(3)
+	policy.MaxBackoff = time.Minute
+	client, _ := NewHttpApiClient("test-key", WithBaseURL(server.URL+"/"), WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Latest(ctx, nil)
+	if !IsHTTPError(err) {
+		t.Fatalf("Expected HTTPError, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to give up without waiting past the deadline, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+
+	if retryAfter, ok := GetRetryAfter(err); !ok || retryAfter != 5*time.Second {
+		t.Errorf("GetRetryAfter() = %v, %v, want 5s, true", retryAfter, ok)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(2)
+		if got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("backoff(2) with jitter = %v, want within [100ms, 300ms]", got)
+		}
+	}
+
+	// Jitter never pushes a delay past MaxBackoff
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(5); got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("backoff(5) with jitter = %v, want within [500ms, 1s]", got)
+		}
+	}
+}
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	tests := []struct {
+		name      string
+		err       error
+		want      time.Duration
+		wantRetry bool
+	}{
+		{name: "backoff", err: &HTTPError{StatusCode: 503}, want: 100 * time.Millisecond, wantRetry: true},
+		{name: "retry after", err: &HTTPError{StatusCode: 429, RetryAfter: time.Second}, want: time.Second, wantRetry: true},
+		{name: "retry after over max backoff", err: &HTTPError{StatusCode: 429, RetryAfter: 2 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, retry := policy.nextDelay(context.Background(), 1, tt.err)
+			if got != tt.want || retry != tt.wantRetry {
+				t.Errorf("nextDelay() = %v, %v, want %v, %v", got, retry, tt.want, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("120"); got != 2*time.Minute {
+		t.Errorf("parseRetryAfter(\"120\") = %v, want 2m", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+	if got := parseRetryAfter("soon"); got != 0 {
+		t.Errorf("parseRetryAfter(\"soon\") = %v, want 0", got)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(%q) = %v, want about 1h", date, got)
+	}
+}
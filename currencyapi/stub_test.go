@@ -0,0 +1,83 @@
+package currencyapi
+
+import (
+	"context"
+	"sync"
+)
+
+// stubClient is a minimal Client used to test decorators
+type stubClient struct {
+	status    StatusResponse
+	latest    LatestResponse
+	err       error
+	calls     map[string]int
+	mu        sync.Mutex
+	onRequest func(endpoint string)
+}
+
+func newStubClient() *stubClient {
+	return &stubClient{calls: make(map[string]int)}
+}
+
+func (s *stubClient) record(endpoint string) error {
+	s.mu.Lock()
+	s.calls[endpoint]++
+	hook, err := s.onRequest, s.err
+	s.mu.Unlock()
+
+	if hook != nil {
+		hook(endpoint)
+	}
+	return err
+}
+
+func (s *stubClient) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *stubClient) count(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[endpoint]
+}
+
+func (s *stubClient) Status(ctx context.Context) (*StatusResponse, error) {
+	if err := s.record("status"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	return &status, nil
+}
+
+func (s *stubClient) Currencies(ctx context.Context, params *CurrenciesParams) (*CurrenciesResponse, error) {
+	if err := s.record("currencies"); err != nil {
+		return nil, err
+	}
+	return &CurrenciesResponse{Data: map[string]CurrencyInfo{"EUR": {Code: "EUR"}}}, nil
+}
+
+func (s *stubClient) Latest(ctx context.Context, params *LatestParams) (*LatestResponse, error) {
+	if err := s.record("latest"); err != nil {
+		return nil, err
+	}
+	latest := s.latest
+	return &latest, nil
+}
+
+func (s *stubClient) Historical(ctx context.Context, params *HistoricalParams) (*HistoricalResponse, error) {
+	if err := s.record("historical"); err != nil {
+		return nil, err
+	}
+	return &HistoricalResponse{Data: s.latest.Data}, nil
+}
+
+func (s *stubClient) Convert(ctx context.Context, params *ConvertParams) (*ConvertResponse, error) {
+	if err := s.record("convert"); err != nil {
+		return nil, err
+	}
+	return &ConvertResponse{}, nil
+}
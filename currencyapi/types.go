@@ -1,5 +1,7 @@
 package currencyapi
 
+import "github.com/BohdanKyryliuk/golang/money"
+
 // StatusResponse represents the response from the status endpoint
 type StatusResponse struct {
 	AccountID int64 `json:"account_id"`
@@ -36,6 +38,11 @@ type CurrencyInfo struct {
 	Countries     []string `json:"countries"`
 }
 
+// Round rounds an amount to the minor unit of the currency following DecimalDigits and Rounding
+func (c CurrencyInfo) Round(amount money.Amount, mode money.RoundingMode) money.Amount {
+	return amount.RoundForCurrency(c.DecimalDigits, c.Rounding, mode)
+}
+
 // CurrenciesResponse represents the response from the currencies endpoint
 type CurrenciesResponse struct {
 	Data map[string]CurrencyInfo `json:"data"`
@@ -49,15 +56,20 @@ type LatestParams struct {
 
 // RateInfo represents exchange rate information
 type RateInfo struct {
-	Code  string  `json:"code"`
-	Value float64 `json:"value"`
+	Code  string       `json:"code"`
+	Value money.Amount `json:"value"`
+}
+
+// ResponseMeta represents the metadata of a rates response
+type ResponseMeta struct {
+	LastUpdatedAt string `json:"last_updated_at"`
+	// Provider names the rate provider that served the response, e.g. "currencyapi" or "ecb"
+	Provider string `json:"provider,omitempty"`
 }
 
 // LatestResponse represents the response from the latest endpoint
 type LatestResponse struct {
-	Meta struct {
-		LastUpdatedAt string `json:"last_updated_at"`
-	} `json:"meta"`
+	Meta ResponseMeta        `json:"meta"`
 	Data map[string]RateInfo `json:"data"`
 }
 
@@ -70,30 +82,26 @@ type HistoricalParams struct {
 
 // HistoricalResponse represents the response from the historical endpoint
 type HistoricalResponse struct {
-	Meta struct {
-		LastUpdatedAt string `json:"last_updated_at"`
-	} `json:"meta"`
+	Meta ResponseMeta        `json:"meta"`
 	Data map[string]RateInfo `json:"data"`
 }
 
 // ConvertParams represents parameters for the convert endpoint
 type ConvertParams struct {
-	BaseCurrency string   // Base currency code
-	Currencies   []string // Target currency codes
-	Value        float64  // Amount to convert
-	Date         string   // Optional: Date for historical conversion (YYYY-MM-DD)
+	BaseCurrency string       // Base currency code
+	Currencies   []string     // Target currency codes
+	Value        money.Amount // Amount to convert
+	Date         string       // Optional: Date for historical conversion (YYYY-MM-DD)
 }
 
 // ConvertRateInfo represents conversion rate information
 type ConvertRateInfo struct {
-	Code  string  `json:"code"`
-	Value float64 `json:"value"`
+	Code  string       `json:"code"`
+	Value money.Amount `json:"value"`
 }
 
 // ConvertResponse represents the response from the convert endpoint
 type ConvertResponse struct {
-	Meta struct {
-		LastUpdatedAt string `json:"last_updated_at"`
-	} `json:"meta"`
+	Meta ResponseMeta               `json:"meta"`
 	Data map[string]ConvertRateInfo `json:"data"`
 }
@@ -0,0 +1,221 @@
+// Package auth authenticates the clients of the server with API keys, checks the
+// scopes of their keys and enforces their monthly request budgets.
+package auth
+
+import (
+	"crypto/sha256"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/config"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderAPIKey carries an API key as an alternative to a bearer token
+const HeaderAPIKey = "X-API-Key"
+
+// Gin context keys
+const (
+	keyContextKey     = "auth_key"
+	meteredContextKey = "auth_metered"
+)
+
+// key is an API key and its usage in the current month
+type key struct {
+	name   string
+	scopes []string
+	budget int // Requests per month; 0 means unlimited
+
+	period string // Month of used, e.g. 2026-01
+	used   int
+}
+
+// allows reports whether the key grants a scope; admin grants every scope
+func (k *key) allows(scope string) bool {
+	return slices.Contains(k.scopes, scope) || slices.Contains(k.scopes, config.ScopeAdmin)
+}
+
+// Usage is the request budget usage of an API key in the current month
+type Usage struct {
+	Key    string   `json:"key"`
+	Scopes []string `json:"scopes"`
+	Period string   `json:"period"` // Calendar month in UTC, e.g. 2026-01
+	Used   int      `json:"used"`
+	// Budget and Remaining are omitted for keys without a budget
+	Budget    int       `json:"budget,omitempty"`
+	Remaining *int      `json:"remaining,omitempty"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// Authenticator checks the API keys of requests and counts them against the budgets of the keys
+type Authenticator struct {
+	keys map[[sha256.Size]byte]*key // By hash, so that lookups don't leak timing of key prefixes
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+// Option is a functional option for configuring an Authenticator
+type Option func(*Authenticator)
+
+// WithNow sets the function returning the current time, which selects the budget period
+func WithNow(now func() time.Time) Option {
+	return func(a *Authenticator) {
+		a.now = now
+	}
+}
+
+// New creates an authenticator accepting the keys of cfg
+func New(cfg *config.AuthConfig, opts ...Option) (*Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	a := &Authenticator{
+		keys: make(map[[sha256.Size]byte]*key, len(cfg.Keys)),
+		now:  time.Now,
+	}
+	for _, k := range cfg.Keys {
+		a.keys[sha256.Sum256([]byte(k.Key))] = &key{
+			name:   k.Name,
+			scopes: slices.Clone(k.Scopes),
+			budget: k.MonthlyBudget,
+		}
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+// Require returns a middleware that admits requests with a valid API key granting scope
+// and counts them against the budget of the key, once per request however many scopes
+// are required. Requests over budget get a 429 until the next month.
+func (a *Authenticator) Require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k, ok := a.authenticate(c)
+		if !ok {
+			return
+		}
+		if !k.allows(scope) {
+			p := problem.New(problem.TypeForbidden, 403, "the API key "+k.name+" lacks the "+scope+" scope")
+			p.RequiredScope = scope
+			problem.Abort(c, p)
+			return
+		}
+		if !c.GetBool(meteredContextKey) {
+			c.Set(meteredContextKey, true)
+			if !a.meter(c, k) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// Usage returns the budget usage of the API key of the request, aborting the request
+// with a 401 if the key is missing or unknown. Looking up the usage doesn't count
+// against the budget.
+func (a *Authenticator) Usage(c *gin.Context) (Usage, bool) {
+	k, ok := a.authenticate(c)
+	if !ok {
+		return Usage{}, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now().UTC()
+	k.roll(now)
+	usage := Usage{
+		Key:      k.name,
+		Scopes:   k.scopes,
+		Period:   k.period,
+		Used:     k.used,
+		Budget:   k.budget,
+		ResetsAt: nextMonth(now),
+	}
+	if k.budget > 0 {
+		remaining := max(k.budget-k.used, 0)
+		usage.Remaining = &remaining
+	}
+	return usage, true
+}
+
+// authenticate returns the key of the request, aborting it with a 401 if the key is
+// missing or unknown
+func (a *Authenticator) authenticate(c *gin.Context) (*key, bool) {
+	if k, ok := c.Get(keyContextKey); ok {
+		return k.(*key), true
+	}
+
+	value := c.GetHeader(HeaderAPIKey)
+	if authorization := c.GetHeader("Authorization"); value == "" && authorization != "" {
+		scheme, token, found := strings.Cut(authorization, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			value = strings.TrimSpace(token)
+		}
+	}
+	if value == "" {
+		a.unauthorized(c, "an API key is required in the Authorization header as a bearer token or in the "+HeaderAPIKey+" header")
+		return nil, false
+	}
+
+	k, ok := a.keys[sha256.Sum256([]byte(value))]
+	if !ok {
+		a.unauthorized(c, "the API key is not valid")
+		return nil, false
+	}
+	c.Set(keyContextKey, k)
+	return k, true
+}
+
+func (a *Authenticator) unauthorized(c *gin.Context, detail string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	problem.Abort(c, problem.New(problem.TypeUnauthorized, 401, detail))
+}
+
+// meter counts a request against the budget of its key and sets the rate limit
+// headers, aborting the request with a 429 if the budget is spent
+func (a *Authenticator) meter(c *gin.Context, k *key) bool {
+	a.mu.Lock()
+	now := a.now().UTC()
+	k.roll(now)
+	allowed := k.budget == 0 || k.used < k.budget
+	if allowed {
+		k.used++
+	}
+	used, budget, period := k.used, k.budget, k.period
+	a.mu.Unlock()
+
+	if budget == 0 {
+		return true
+	}
+	resetsAt := nextMonth(now)
+	c.Header("X-RateLimit-Limit", strconv.Itoa(budget))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(budget-used))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(resetsAt.Unix(), 10))
+	if !allowed {
+		p := problem.New(problem.TypeBudgetExceeded, 429,
+			"the API key "+k.name+" has used its "+strconv.Itoa(budget)+" requests of "+period)
+		p.RetryAfter = int(math.Ceil(resetsAt.Sub(now).Seconds()))
+		problem.Abort(c, p)
+	}
+	return allowed
+}
+
+// roll resets the usage of the key when a new month starts; Authenticator.mu must be held
+func (k *key) roll(now time.Time) {
+	if period := now.Format("2006-01"); k.period != period {
+		k.period = period
+		k.used = 0
+	}
+}
+
+// nextMonth returns the start of the month after t, in UTC
+func nextMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/config"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	readerKey = "reader-0123456789abcdef"
+	adminKey  = "admin-0123456789abcdef"
+)
+
+// newAuthRouter serves a read route, an admin route and the usage of a reader key
+// with a budget of 3 requests and an unlimited admin key
+func newAuthRouter(t *testing.T, now *time.Time) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	a, err := New(&config.AuthConfig{Keys: []config.APIKey{
+		{Name: "reader", Key: readerKey, Scopes: []string{config.ScopeRatesRead}, MonthlyBudget: 3},
+		{Name: "admin", Key: adminKey, Scopes: []string{config.ScopeAdmin}},
+	}}, WithNow(func() time.Time { return *now }))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ok := func(c *gin.Context) { c.String(200, "ok") }
+	router := gin.New()
+	read := router.Group("/rates", a.Require(config.ScopeRatesRead))
+	read.GET("", ok)
+	read.POST("/workers", a.Require(config.ScopeAdmin), ok)
+	router.GET("/me/usage", func(c *gin.Context) {
+		if usage, ok := a.Usage(c); ok {
+			c.JSON(200, usage)
+		}
+	})
+	return router
+}
+
+func request(router *gin.Engine, method, target string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func problemType(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p problem.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("Invalid problem response: %v", err)
+	}
+	return p.Type
+}
+
+func TestRequire(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	router := newAuthRouter(t, &now)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		header   []string
+		want     int
+		wantType string
+	}{
+		{name: "no key", method: "GET", target: "/rates", want: 401, wantType: problem.TypeUnauthorized},
+		{name: "unknown key", method: "GET", target: "/rates", header: []string{HeaderAPIKey, "unknown-0123456789abcdef"}, want: 401, wantType: problem.TypeUnauthorized},
+		{name: "basic auth", method: "GET", target: "/rates", header: []string{"Authorization", "Basic " + readerKey}, want: 401, wantType: problem.TypeUnauthorized},
+		{name: "bearer token", method: "GET", target: "/rates", header: []string{"Authorization", "Bearer " + readerKey}, want: 200},
+		{name: "api key header", method: "GET", target: "/rates", header: []string{HeaderAPIKey, readerKey}, want: 200},
+		{name: "missing scope", method: "POST", target: "/rates/workers", header: []string{HeaderAPIKey, readerKey}, want: 403, wantType: problem.TypeForbidden},
+		{name: "admin", method: "POST", target: "/rates/workers", header: []string{"Authorization", "bearer " + adminKey}, want: 200},
+		{name: "admin reads", method: "GET", target: "/rates", header: []string{HeaderAPIKey, adminKey}, want: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := request(router, tt.method, tt.target, tt.header...)
+			if w.Code != tt.want {
+				t.Fatalf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+			if tt.wantType != "" && problemType(t, w) != tt.wantType {
+				t.Errorf("Unexpected problem: %s", w.Body)
+			}
+			if tt.want == 401 && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestBudget(t *testing.T) {
+	now := time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC)
+	router := newAuthRouter(t, &now)
+
+	for i := range 3 {
+		w := request(router, "GET", "/rates", HeaderAPIKey, readerKey)
+		if w.Code != 200 {
+			t.Fatalf("Request %d: expected 200, got %d: %s", i+1, w.Code, w.Body)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != []string{"2", "1", "0"}[i] {
+			t.Errorf("Request %d: X-RateLimit-Remaining = %s", i+1, got)
+		}
+	}
+
+	w := request(router, "GET", "/rates", HeaderAPIKey, readerKey)
+	if w.Code != 429 || problemType(t, w) != problem.TypeBudgetExceeded {
+		t.Fatalf("Expected a 429 budget problem, got %d: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %s, want 60", got)
+	}
+
+	// Usage requests are free and report the spent budget
+	var usage Usage
+	w = request(router, "GET", "/me/usage", "Authorization", "Bearer "+readerKey)
+	if err := json.Unmarshal(w.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("Invalid usage response %d: %v", w.Code, err)
+	}
+	if usage.Key != "reader" || usage.Period != "2026-01" || usage.Used != 3 || usage.Budget != 3 ||
+		usage.Remaining == nil || *usage.Remaining != 0 || !usage.ResetsAt.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+
+	// The budget resets with the month, and unlimited keys are never rejected
+	now = now.Add(time.Minute)
+	if w := request(router, "GET", "/rates", HeaderAPIKey, readerKey); w.Code != 200 {
+		t.Errorf("Expected 200 in a new month, got %d: %s", w.Code, w.Body)
+	}
+	w = request(router, "GET", "/me/usage", HeaderAPIKey, adminKey)
+	usage = Usage{}
+	if err := json.Unmarshal(w.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("Invalid usage response %d: %v", w.Code, err)
+	}
+	if usage.Key != "admin" || usage.Period != "2026-02" || usage.Budget != 0 || usage.Remaining != nil {
+		t.Errorf("Unexpected admin usage: %+v", usage)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []config.APIKey
+	}{
+		{name: "no keys"},
+		{name: "short key", keys: []config.APIKey{{Name: "a", Key: "short", Scopes: []string{config.ScopeAdmin}}}},
+		{name: "unknown scope", keys: []config.APIKey{{Name: "a", Key: readerKey, Scopes: []string{"rates:write"}}}},
+		{name: "duplicate key", keys: []config.APIKey{
+			{Name: "a", Key: readerKey, Scopes: []string{config.ScopeAdmin}},
+			{Name: "b", Key: readerKey, Scopes: []string{config.ScopeAdmin}},
+		}},
+		{name: "negative budget", keys: []config.APIKey{{Name: "a", Key: readerKey, Scopes: []string{config.ScopeAdmin}, MonthlyBudget: -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(&config.AuthConfig{Keys: tt.keys}); err == nil {
+				t.Error("New() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"github.com/BohdanKyryliuk/golang/http/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// Account holds the dependencies for the HTTP handlers about the API key of a request
+type Account struct {
+	authenticator *auth.Authenticator
+}
+
+// NewAccount creates a new Account handler for the keys of the authenticator
+func NewAccount(authenticator *auth.Authenticator) *Account {
+	return &Account{authenticator: authenticator}
+}
+
+// Usage handles requests for the budget usage of the API key of the request
+func (h *Account) Usage(c *gin.Context) {
+	usage, ok := h.authenticator.Usage(c)
+	if !ok {
+		return
+	}
+	render(c, 200, usage)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/config"
+	"github.com/BohdanKyryliuk/golang/http/auth"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/gin-gonic/gin"
+)
+
+const accountKey = "reader-0123456789abcdef"
+
+func TestAccountUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authenticator, err := auth.New(&config.AuthConfig{Keys: []config.APIKey{
+		{Name: "reader", Key: accountKey, Scopes: []string{config.ScopeRatesRead}, MonthlyBudget: 100},
+	}})
+	if err != nil {
+		t.Fatalf("auth.New() error = %v", err)
+	}
+	router := gin.New()
+	router.GET("/me/usage", NewAccount(authenticator).Usage)
+
+	tests := []struct {
+		name        string
+		key         string
+		accept      string
+		want        int
+		contentType string
+		body        string
+	}{
+		{name: "json", key: accountKey, want: http.StatusOK, contentType: mimeJSON, body: `"key":"reader"`},
+		{name: "xml", key: accountKey, accept: mimeXML, want: http.StatusOK, contentType: mimeXML, body: "<key>reader</key>"},
+		{name: "csv", key: accountKey, accept: mimeCSV, want: http.StatusNotAcceptable, contentType: problem.ContentType},
+		{name: "no key", want: http.StatusUnauthorized, contentType: problem.ContentType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me/usage", nil)
+			if tt.key != "" {
+				req.Header.Set(auth.HeaderAPIKey, tt.key)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Content-Type = %s, want %s", got, tt.contentType)
+			}
+			if !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("Expected %s in the body, got %s", tt.body, w.Body)
+			}
+		})
+	}
+}
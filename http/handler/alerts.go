@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/BohdanKyryliuk/golang/alert"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Alerts holds the dependencies for alert rule HTTP handlers
+type Alerts struct {
+	rules *alert.Rules
+}
+
+// NewAlerts creates a new Alerts handler managing the given rules
+func NewAlerts(rules *alert.Rules) *Alerts {
+	return &Alerts{rules: rules}
+}
+
+// ruleList is the alert rules in the order they were added
+type ruleList struct {
+	Rules []alert.Rule `json:"rules"`
+}
+
+// List handles requests for all alert rules
+func (h *Alerts) List(c *gin.Context) {
+	render(c, 200, ruleList{Rules: h.rules.List()})
+}
+
+// Get handles requests for an alert rule
+// Path params: id (rule ID)
+func (h *Alerts) Get(c *gin.Context) {
+	rule, err := h.rules.Get(c.Param("id"))
+	if err != nil {
+		problem.Abort(c, err)
+		return
+	}
+	render(c, 200, rule)
+}
+
+// Create handles requests to add an alert rule, given as JSON
+func (h *Alerts) Create(c *gin.Context) {
+	var rule alert.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		problem.Abort(c, problem.InvalidRequest("invalid alert rule: "+err.Error()))
+		return
+	}
+	rule, err := h.rules.Create(rule)
+	if err != nil {
+		problem.Abort(c, err)
+		return
+	}
+	render(c, 201, rule)
+}
+
+// Update handles requests to replace an alert rule, given as JSON
+// Path params: id (rule ID)
+func (h *Alerts) Update(c *gin.Context) {
+	var rule alert.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		problem.Abort(c, problem.InvalidRequest("invalid alert rule: "+err.Error()))
+		return
+	}
+	rule, err := h.rules.Update(c.Param("id"), rule)
+	if err != nil {
+		problem.Abort(c, err)
+		return
+	}
+	render(c, 200, rule)
+}
+
+// Delete handles requests to remove an alert rule
+// Path params: id (rule ID)
+func (h *Alerts) Delete(c *gin.Context) {
+	if err := h.rules.Delete(c.Param("id")); err != nil {
+		problem.Abort(c, err)
+		return
+	}
+	c.Status(204)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/alert"
+	"github.com/gin-gonic/gin"
+)
+
+func newAlertsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewAlerts(alert.NewRules())
+	router := gin.New()
+	router.GET("/alerts", h.List)
+	router.POST("/alerts", h.Create)
+	router.GET("/alerts/:id", h.Get)
+	router.PUT("/alerts/:id", h.Update)
+	router.DELETE("/alerts/:id", h.Delete)
+	return router
+}
+
+func TestAlerts(t *testing.T) {
+	router := newAlertsRouter()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{name: "create crosses", method: http.MethodPost, target: "/alerts", body: `{"id":"eurusd","base":"eur","quote":"usd","kind":"crosses","threshold":1.10}`, want: http.StatusCreated},
+		{name: "create change", method: http.MethodPost, target: "/alerts", body: `{"id":"gbp","base":"GBP","quote":"USD","kind":"change","change_percent":1,"window":"15m"}`, want: http.StatusCreated},
+		{name: "create duplicate", method: http.MethodPost, target: "/alerts", body: `{"id":"gbp","base":"GBP","quote":"USD","kind":"change","change_percent":1,"window":"15m"}`, want: http.StatusConflict},
+		{name: "create invalid", method: http.MethodPost, target: "/alerts", body: `{"base":"GBP","quote":"USD","kind":"change","change_percent":1}`, want: http.StatusBadRequest},
+		{name: "create malformed", method: http.MethodPost, target: "/alerts", body: `{"window":"soon"}`, want: http.StatusBadRequest},
+		{name: "get", method: http.MethodGet, target: "/alerts/eurusd", want: http.StatusOK},
+		{name: "get unknown", method: http.MethodGet, target: "/alerts/unknown", want: http.StatusNotFound},
+		{name: "update", method: http.MethodPut, target: "/alerts/gbp", body: `{"base":"GBP","quote":"USD","kind":"change","change_percent":2,"window":"1h","cooldown":"30m"}`, want: http.StatusOK},
+		{name: "update unknown", method: http.MethodPut, target: "/alerts/unknown", body: `{"base":"GBP","quote":"USD","kind":"change","change_percent":2,"window":"1h"}`, want: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, target: "/alerts/eurusd", want: http.StatusNoContent},
+		{name: "delete unknown", method: http.MethodDelete, target: "/alerts/eurusd", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+		})
+	}
+
+	w := serve(router, "/alerts")
+	var response struct {
+		Rules []alert.Rule `json:"rules"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if len(response.Rules) != 1 || response.Rules[0].ID != "gbp" || response.Rules[0].ChangePercent.String() != "2" || response.Rules[0].Cooldown.String() != "30m0s" {
+		t.Errorf("Unexpected rules: %+v", response.Rules)
+	}
+}
@@ -1,12 +1,13 @@
 package handler
 
 import (
-	"errors"
-	"log"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/BohdanKyryliuk/golang/currency_converter"
-	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/BohdanKyryliuk/golang/money"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,83 +23,124 @@ func NewCurrency(client *currency_converter.Client) *Currency {
 
 // Status handles requests for currency API status
 func (h *Currency) Status(c *gin.Context) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
-
 	status, err := h.client.CheckStatus(c.Request.Context())
 	if err != nil {
-		handleCurrencyError(c, err)
+		problem.Abort(c, err)
 		return
 	}
 
-	c.String(200, "%s", status)
+	render(c, 200, status)
 }
 
 // Currencies handles requests for available currencies
 func (h *Currency) Currencies(c *gin.Context) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
-
 	currencies, err := h.client.GetCurrencies(c.Request.Context())
 	if err != nil {
-		handleCurrencyError(c, err)
+		problem.Abort(c, err)
 		return
 	}
 
-	c.String(200, "%s", currencies)
+	render(c, 200, currencies)
 }
 
 // LatestRates handles requests for latest exchange rates
 // Query params: base (base currency), currencies (comma-separated list)
 func (h *Currency) LatestRates(c *gin.Context) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
-
 	// Parse query parameters
 	params := &currency_converter.LatestRatesParams{
-		BaseCurrency: c.Query("base"),
-	}
-	if currencies := c.Query("currencies"); currencies != "" {
-		params.Currencies = strings.Split(currencies, ",")
+		BaseCurrency: strings.ToUpper(c.Query("base")),
+		Currencies:   splitCurrencies(c.Query("currencies")),
 	}
 
 	rates, err := h.client.GetLatestRates(c.Request.Context(), params)
 	if err != nil {
-		handleCurrencyError(c, err)
+		problem.Abort(c, err)
 		return
 	}
 
-	c.String(200, "%s", rates)
+	render(c, 200, rateTable{rates})
 }
 
-// handleCurrencyError handles errors from the currency converter with appropriate HTTP responses
-func handleCurrencyError(c *gin.Context, err error) {
-	log.Printf("Currency API error: %v", err)
-
-	// Check for specific error types and set appropriate status codes
-	var apiErr *currencyapi.APIError
-	if errors.As(err, &apiErr) {
-		if apiErr.IsInvalidAPIKey() {
-			c.AbortWithStatusJSON(500, gin.H{"error": "Service configuration error"})
-			return
-		}
-		if apiErr.IsQuotaExceeded() {
-			c.AbortWithStatusJSON(503, gin.H{"error": "Service temporarily unavailable, please try again later"})
-			return
-		}
+// HistoricalRates handles requests for exchange rates of a past date
+// Query params: date (YYYY-MM-DD, required), base (base currency), currencies (comma-separated list)
+func (h *Currency) HistoricalRates(c *gin.Context) {
+	params := &currency_converter.HistoricalRatesParams{
+		Date:         c.Query("date"),
+		BaseCurrency: strings.ToUpper(c.Query("base")),
+		Currencies:   splitCurrencies(c.Query("currencies")),
+	}
+
+	rates, err := h.client.GetHistoricalRates(c.Request.Context(), params)
+	if err != nil {
+		problem.Abort(c, err)
+		return
+	}
+
+	render(c, 200, rateTable{rates})
+}
+
+// Convert handles requests for converting an amount to other currencies
+// Query params: from (source currency), to (comma-separated list), amount (decimal), date (YYYY-MM-DD, optional)
+func (h *Currency) Convert(c *gin.Context) {
+	rawAmount := c.Query("amount")
+	if rawAmount == "" {
+		problem.Abort(c, problem.InvalidRequest("amount parameter is required"))
+		return
+	}
+	amount, err := money.Parse(rawAmount)
+	if err != nil {
+		problem.Abort(c, problem.InvalidRequest("amount must be a decimal number"))
+		return
 	}
 
-	var httpErr *currencyapi.HTTPError
-	if errors.As(err, &httpErr) {
-		if httpErr.IsRateLimited() {
-			c.AbortWithStatusJSON(429, gin.H{"error": "Rate limited, please try again later"})
-			return
-		}
+	params := &currency_converter.ConvertParams{
+		From:   strings.ToUpper(c.Query("from")),
+		To:     splitCurrencies(c.Query("to")),
+		Amount: amount,
+		Date:   c.Query("date"),
 	}
 
-	// Check if it's a temporary error
-	if currencyapi.IsTemporaryError(err) {
-		c.AbortWithStatusJSON(503, gin.H{"error": "Service temporarily unavailable"})
+	converted, err := h.client.Convert(c.Request.Context(), params)
+	if err != nil {
+		problem.Abort(c, err)
 		return
 	}
 
-	// Default error response
-	c.AbortWithStatusJSON(500, gin.H{"error": "Failed to fetch currency data"})
+	render(c, 200, conversion{converted})
+}
+
+// rateTable renders a rate table, one CSV record per rate
+type rateTable struct {
+	*currency_converter.RateTable
+}
+
+func (t rateTable) csvRecords() [][]string {
+	records := [][]string{{"base", "quote", "rate", "date", "last_updated_at"}}
+	for _, code := range slices.Sorted(maps.Keys(t.Rates)) {
+		records = append(records, []string{t.BaseCurrency, code, t.Rates[code].String(), t.Date, t.LastUpdatedAt})
+	}
+	return records
+}
+
+// conversion renders a conversion, one CSV record per target currency
+type conversion struct {
+	*currency_converter.Conversion
+}
+
+func (c conversion) csvRecords() [][]string {
+	records := [][]string{{"from", "amount", "to", "result", "date", "last_updated_at"}}
+	for _, code := range slices.Sorted(maps.Keys(c.Results)) {
+		records = append(records, []string{
+			c.Amount.Currency, c.Amount.Amount.String(), code, c.Results[code].Amount.String(), c.Date, c.LastUpdatedAt,
+		})
+	}
+	return records
+}
+
+// splitCurrencies parses a comma-separated list of currency codes
+func splitCurrencies(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(strings.ToUpper(value), ",")
 }
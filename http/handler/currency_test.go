@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BohdanKyryliuk/golang/currency_converter"
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/gin-gonic/gin"
+)
+
+// newCurrencyRouter serves the currency handlers from a fake API client
+func newCurrencyRouter(t *testing.T) (*gin.Engine, *currencyapitest.FakeClient) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	fake := currencyapitest.NewFakeClient("USD", map[string]float64{"EUR": 0.8, "UAH": 40})
+	fake.SetHistoricalRates("2024-01-02", map[string]float64{"EUR": 0.9, "UAH": 38})
+	client, err := currency_converter.NewWithAPIClient(currency_converter.Config{}, fake)
+	if err != nil {
+		t.Fatalf("NewWithAPIClient() error = %v", err)
+	}
+
+	h := NewCurrency(client)
+	router := gin.New()
+	router.GET("/currency/latest", h.LatestRates)
+	router.GET("/currency/historical", h.HistoricalRates)
+	router.GET("/currency/convert", h.Convert)
+	return router, fake
+}
+
+func serve(router *gin.Engine, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestCurrencyLatestRates(t *testing.T) {
+	router, fake := newCurrencyRouter(t)
+
+	w := serve(router, "/currency/latest?base=eur&currencies=usd,uah")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var table currency_converter.RateTable
+	if err := json.Unmarshal(w.Body.Bytes(), &table); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if table.BaseCurrency != "EUR" || len(table.Rates) != 2 || table.Rates["UAH"].String() != "50" {
+		t.Errorf("Unexpected rate table: %+v", table)
+	}
+
+	// Malformed codes are rejected without spending an upstream call
+	fake.ResetCalls()
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: "long base", target: "/currency/latest?base=EURO"},
+		{name: "non-letter base", target: "/currency/latest?base=U5D"},
+		{name: "malformed currency", target: "/currency/latest?currencies=EUR,U"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(router, tt.target); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d: %s", w.Code, w.Body)
+			}
+		})
+	}
+	if n := fake.CallCount(currencyapitest.EndpointLatest); n != 0 {
+		t.Errorf("Expected no API calls, got %d", n)
+	}
+}
+
+func TestCurrencyHistoricalRates(t *testing.T) {
+	router, _ := newCurrencyRouter(t)
+
+	w := serve(router, "/currency/historical?date=2024-01-02&base=eur&currencies=usd,uah")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+
+	var table currency_converter.RateTable
+	if err := json.Unmarshal(w.Body.Bytes(), &table); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if table.BaseCurrency != "EUR" || table.Date != "2024-01-02" || table.Provider != currencyapitest.ProviderName {
+		t.Errorf("Unexpected rate table: %+v", table)
+	}
+	if len(table.Rates) != 2 || table.Rates["UAH"].String() != "42.222222222222222222" {
+		t.Errorf("Unexpected historical rates: %+v", table.Rates)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{name: "missing date", target: "/currency/historical", want: http.StatusBadRequest},
+		{name: "malformed date", target: "/currency/historical?date=02.01.2024", want: http.StatusBadRequest},
+		{name: "future date", target: "/currency/historical?date=2999-01-01", want: http.StatusBadRequest},
+		{name: "unknown currency", target: "/currency/historical?date=2024-01-02&currencies=XYZ", want: http.StatusBadRequest},
+		{name: "malformed base", target: "/currency/historical?date=2024-01-02&base=E", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(router, tt.target); w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestCurrencyConvert(t *testing.T) {
+	router, fake := newCurrencyRouter(t)
+
+	w := serve(router, "/currency/convert?from=usd&to=EUR,UAH&amount=100.10")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+
+	var conversion currency_converter.Conversion
+	if err := json.Unmarshal(w.Body.Bytes(), &conversion); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if conversion.Amount.String() != "100.10 USD" {
+		t.Errorf("Unexpected amount: %s", conversion.Amount)
+	}
+	if conversion.Results["EUR"].String() != "80.080 EUR" || conversion.Results["UAH"].String() != "4004.00 UAH" {
+		t.Errorf("Unexpected conversion: %+v", conversion.Results)
+	}
+
+	w = serve(router, "/currency/convert?from=USD&to=EUR&amount=100&date=2024-01-02")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	conversion = currency_converter.Conversion{}
+	if err := json.Unmarshal(w.Body.Bytes(), &conversion); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if conversion.Results["EUR"].String() != "90.0 EUR" || conversion.Date != "2024-01-02" {
+		t.Errorf("Unexpected historical conversion: %+v", conversion)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/currency/convert?from=USD&to=UAH,EUR&amount=100.10", nil)
+	req.Header.Set("Accept", "text/csv")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	// One record per target currency, the fake's update time in the last column
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV response: %v", err)
+	}
+	var got []string
+	for _, record := range records {
+		got = append(got, strings.Join(record[:5], ","))
+	}
+	want := []string{"from,amount,to,result,date", "USD,100.10,EUR,80.080,", "USD,100.10,UAH,4004.00,"}
+	if !slices.Equal(got, want) {
+		t.Errorf("CSV conversion = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{name: "missing amount", target: "/currency/convert?from=USD&to=EUR", want: http.StatusBadRequest},
+		{name: "malformed amount", target: "/currency/convert?from=USD&to=EUR&amount=1,5", want: http.StatusBadRequest},
+		{name: "negative amount", target: "/currency/convert?from=USD&to=EUR&amount=-1", want: http.StatusBadRequest},
+		{name: "missing from", target: "/currency/convert?to=EUR&amount=1", want: http.StatusBadRequest},
+		{name: "missing to", target: "/currency/convert?from=USD&amount=1", want: http.StatusBadRequest},
+		{name: "malformed date", target: "/currency/convert?from=USD&to=EUR&amount=1&date=yesterday", want: http.StatusBadRequest},
+		{name: "malformed from", target: "/currency/convert?from=US&to=EUR&amount=1", want: http.StatusBadRequest},
+		{name: "malformed to", target: "/currency/convert?from=USD&to=EUR,12A&amount=1", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(router, tt.target); w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+		})
+	}
+
+	// Provider failures are mapped like on the other currency endpoints
+	fake.SetError(currencyapitest.EndpointConvert, &currencyapi.HTTPError{StatusCode: 503})
+	if w := serve(router, "/currency/convert?from=USD&to=EUR&amount=1"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,288 @@
+package handler
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BohdanKyryliuk/golang/alert"
+	"github.com/BohdanKyryliuk/golang/config"
+	"github.com/BohdanKyryliuk/golang/currency_converter"
+	"github.com/BohdanKyryliuk/golang/http/auth"
+	"github.com/BohdanKyryliuk/golang/http/openapi"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/BohdanKyryliuk/golang/money"
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// docsPage renders the OpenAPI document served at /openapi.json
+//
+//go:embed docs.html
+var docsPage []byte
+
+// spec is the OpenAPI document, built on first use
+var spec = sync.OnceValue(OpenAPI)
+
+// ServeOpenAPI handles requests for the OpenAPI document of the API
+func ServeOpenAPI(c *gin.Context) {
+	c.JSON(200, spec())
+}
+
+// Docs handles requests for the API documentation page
+func Docs(c *gin.Context) {
+	c.Data(200, "text/html; charset=utf-8", docsPage)
+}
+
+// OpenAPI builds the OpenAPI document of the currency, rates and alert endpoints
+func OpenAPI() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Currency API",
+		Description: "Currency data from CurrencyAPI and its fallbacks, and the rates cached by the background workers.",
+		Version:     "1.0.0",
+	})
+	doc.Tags = []openapi.Tag{
+		{Name: "currency", Description: "Requests to the currency data provider"},
+		{Name: "rates", Description: "Rates cached by the background workers"},
+		{Name: "alerts", Description: "Threshold alert rules"},
+		{Name: "account", Description: "Usage of the API key of the request"},
+	}
+	doc.Define(money.Amount{}, &openapi.Schema{Type: "number", Description: "Exact decimal number"})
+
+	base := openapi.QueryParam("base", "Base currency code", false)
+	currencies := openapi.QueryParam("currencies", "Comma-separated quote currency codes (default: all)", false)
+	date := openapi.QueryParam("date", "Date of past rates (YYYY-MM-DD)", false)
+
+	doc.Add("GET", "/currency/status", &openapi.Operation{
+		Summary:    "Account and quota status of the currency data provider",
+		Tags:       []string{"currency"},
+		Parameters: []openapi.Parameter{prettyParam},
+		Responses:  rendered(doc, "Quota usage", currency_converter.StatusSummary{}, false),
+	})
+	doc.Add("GET", "/currency/currencies", &openapi.Operation{
+		Summary:    "Supported currencies",
+		Tags:       []string{"currency"},
+		Parameters: []openapi.Parameter{prettyParam},
+		Responses:  rendered(doc, "Currencies by code", currency_converter.CurrencyCatalog{}, false),
+	})
+	doc.Add("GET", "/currency/latest", &openapi.Operation{
+		Summary:    "Latest exchange rates",
+		Tags:       []string{"currency"},
+		Parameters: []openapi.Parameter{base, currencies, prettyParam},
+		Responses:  rendered(doc, "Value of one unit of the base currency in the quote currencies", currency_converter.RateTable{}, true),
+	})
+	doc.Add("GET", "/currency/historical", &openapi.Operation{
+		Summary:    "Exchange rates of a past date",
+		Tags:       []string{"currency"},
+		Parameters: []openapi.Parameter{required(date), base, currencies, prettyParam},
+		Responses:  rendered(doc, "Value of one unit of the base currency in the quote currencies", currency_converter.RateTable{}, true),
+	})
+	doc.Add("GET", "/currency/convert", &openapi.Operation{
+		Summary: "Convert an amount to other currencies",
+		Tags:    []string{"currency"},
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("from", "Source currency code", true),
+			openapi.QueryParam("to", "Comma-separated target currency codes", true),
+			openapi.QueryParam("amount", "Decimal amount in the source currency", true),
+			date,
+			prettyParam,
+		},
+		Responses: rendered(doc, "Amount in the target currencies", currency_converter.Conversion{}, true),
+	})
+
+	doc.Add("GET", "/rates", &openapi.Operation{
+		Summary:    "Cached rates of a base currency",
+		Tags:       []string{"rates"},
+		Parameters: []openapi.Parameter{required(base), prettyParam},
+		Responses:  rendered(doc, "Latest rates fetched by the worker of the base currency", worker.RateData{}, true),
+	})
+	doc.Add("GET", "/rates/all", &openapi.Operation{
+		Summary:    "Cached rates of all base currencies",
+		Tags:       []string{"rates"},
+		Parameters: []openapi.Parameter{prettyParam},
+		Responses:  rendered(doc, "Rates by base currency", map[string]*worker.RateData{}, true),
+	})
+	doc.Add("GET", "/rates/status", &openapi.Operation{
+		Summary:    "Worker status and health",
+		Tags:       []string{"rates"},
+		Parameters: []openapi.Parameter{prettyParam},
+		Responses:  rendered(doc, "Lifecycle state and health of the workers", worker.WorkerStatus{}, false),
+	})
+	agg := openapi.QueryParam("agg", "Aggregation of the samples of an interval (default: last)", false)
+	agg.Schema.Enum = []string{string(worker.AggregateLast), string(worker.AggregateAvg), string(worker.AggregateOHLC)}
+	doc.Add("GET", "/rates/history", &openapi.Operation{
+		Summary: "Rate history of a currency pair",
+		Tags:    []string{"rates"},
+		Parameters: []openapi.Parameter{
+			required(base),
+			openapi.QueryParam("quote", "Quote currency code", true),
+			openapi.QueryParam("from", "Start time (RFC 3339)", false),
+			openapi.QueryParam("to", "End time (RFC 3339)", false),
+			openapi.QueryParam("interval", "Bucket size such as 5m or 1h (default: every sample)", false),
+			agg,
+			prettyParam,
+		},
+		Responses: rendered(doc, "Samples or buckets of the rate", history{}, true),
+	})
+	doc.Add("GET", "/rates/stream", &openapi.Operation{
+		Summary: "Stream rate updates as Server-Sent Events",
+		Description: "Each `rates` event carries a JSON encoded RateUpdated with the event ID as `id`. " +
+			"Idle streams get a heartbeat comment every " + DefaultHeartbeat.String() + ".",
+		Tags: []string{"rates"},
+		Parameters: []openapi.Parameter{
+			base,
+			currencies,
+			openapi.HeaderParam("Last-Event-ID", "Resume after this event ID"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "Event stream",
+				Content:     map[string]*openapi.MediaType{"text/event-stream": {Schema: doc.SchemaOf(worker.RateUpdated{})}},
+			},
+			"default": problemResponse(doc),
+		},
+	})
+	doc.Add("GET", "/ws", &openapi.Operation{
+		Summary: "Subscribe to rate updates over a WebSocket",
+		Description: "Send `{\"type\":\"subscribe\",\"base\":\"USD\",\"quote\":\"EUR\"}` or `unsubscribe` messages for up to " +
+			strconv.Itoa(DefaultMaxSubscriptions) + " currency pairs; the server answers with `subscribed`, " +
+			"`unsubscribed` or `error` messages and sends `rates` messages for the subscribed pairs.",
+		Tags: []string{"rates"},
+		Responses: map[string]*openapi.Response{
+			"101": {Description: "Switching to the WebSocket protocol"},
+			"default": {
+				Description: "Server message",
+				Content:     doc.JSON(wsResponse{}),
+			},
+		},
+	})
+	currency := openapi.PathParam("currency", "Base currency code")
+	doc.Add("POST", "/rates/workers/:currency", &openapi.Operation{
+		Summary:    "Start tracking a base currency",
+		Tags:       []string{"rates"},
+		Parameters: []openapi.Parameter{currency, prettyParam},
+		Responses:  renderedStatus(doc, "201", "Tracked base currencies", currencyList{}, false),
+	})
+	doc.Add("DELETE", "/rates/workers/:currency", &openapi.Operation{
+		Summary:    "Stop tracking a base currency",
+		Tags:       []string{"rates"},
+		Parameters: []openapi.Parameter{currency, prettyParam},
+		Responses:  rendered(doc, "Tracked base currencies", currencyList{}, false),
+	})
+
+	id := openapi.PathParam("id", "Rule ID")
+	ruleBody := &openapi.RequestBody{Required: true, Content: doc.JSON(alert.Rule{})}
+	doc.Add("GET", "/alerts", &openapi.Operation{
+		Summary:    "Alert rules",
+		Tags:       []string{"alerts"},
+		Parameters: []openapi.Parameter{prettyParam},
+		Responses:  rendered(doc, "Rules in the order they were added", ruleList{}, false),
+	})
+	doc.Add("POST", "/alerts", &openapi.Operation{
+		Summary:     "Add an alert rule",
+		Tags:        []string{"alerts"},
+		Parameters:  []openapi.Parameter{prettyParam},
+		RequestBody: ruleBody,
+		Responses:   renderedStatus(doc, "201", "Added rule", alert.Rule{}, false),
+	})
+	doc.Add("GET", "/alerts/:id", &openapi.Operation{
+		Summary:    "Alert rule",
+		Tags:       []string{"alerts"},
+		Parameters: []openapi.Parameter{id, prettyParam},
+		Responses:  rendered(doc, "Rule", alert.Rule{}, false),
+	})
+	doc.Add("PUT", "/alerts/:id", &openapi.Operation{
+		Summary:     "Replace an alert rule",
+		Tags:        []string{"alerts"},
+		Parameters:  []openapi.Parameter{id, prettyParam},
+		RequestBody: ruleBody,
+		Responses:   rendered(doc, "Updated rule", alert.Rule{}, false),
+	})
+	doc.Add("DELETE", "/alerts/:id", &openapi.Operation{
+		Summary:    "Remove an alert rule",
+		Tags:       []string{"alerts"},
+		Parameters: []openapi.Parameter{id},
+		Responses: map[string]*openapi.Response{
+			"204":     {Description: "Rule removed"},
+			"default": problemResponse(doc),
+		},
+	})
+
+	doc.Add("GET", "/me/usage", &openapi.Operation{
+		Summary:     "Request budget usage of the API key",
+		Description: "Only served when API keys are configured. Requests for the usage don't count against the budget.",
+		Tags:        []string{"account"},
+		Parameters:  []openapi.Parameter{prettyParam},
+		Responses:   rendered(doc, "Usage in the current month", auth.Usage{}, false),
+	})
+	secure(doc)
+	return doc
+}
+
+// secure documents the API key schemes and the scope each operation requires, which
+// follows the route groups of the server: management needs admin and data needs rates:read
+func secure(doc *openapi.Document) {
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer", Description: "API key as a bearer token"},
+		"apiKey": {Type: "apiKey", In: "header", Name: auth.HeaderAPIKey},
+	}
+	security := []openapi.SecurityRequirement{{"bearer": {}}, {"apiKey": {}}}
+
+	for path, item := range doc.Paths {
+		for _, op := range *item {
+			op.Security = security
+			scope := config.ScopeRatesRead
+			switch {
+			case path == "/me/usage":
+				continue
+			case strings.HasPrefix(path, "/rates/workers/"), strings.HasPrefix(path, "/alerts"):
+				scope = config.ScopeAdmin
+			}
+			op.Description = strings.TrimSpace(op.Description + " Requires the " + scope + " scope when API keys are configured.")
+		}
+	}
+}
+
+// prettyParam is the query parameter indenting JSON and XML responses
+var prettyParam = openapi.Parameter{
+	Name:        "pretty",
+	In:          "query",
+	Description: "Indent JSON and XML responses",
+	Schema:      &openapi.Schema{Type: "boolean"},
+}
+
+// required returns a copy of a parameter that is required
+func required(param openapi.Parameter) openapi.Parameter {
+	param.Required = true
+	return param
+}
+
+// rendered returns the responses of an endpoint writing v with render
+func rendered(doc *openapi.Document, description string, v any, csv bool) map[string]*openapi.Response {
+	return renderedStatus(doc, "200", description, v, csv)
+}
+
+// renderedStatus returns the responses of an endpoint writing v with render and status
+func renderedStatus(doc *openapi.Document, status, description string, v any, csv bool) map[string]*openapi.Response {
+	schema := doc.SchemaOf(v)
+	content := map[string]*openapi.MediaType{
+		mimeJSON: {Schema: schema},
+		mimeXML:  {Schema: schema},
+	}
+	if csv {
+		content[mimeCSV] = &openapi.MediaType{Schema: &openapi.Schema{Type: "string", Description: "One row per rate"}}
+	}
+	return map[string]*openapi.Response{
+		status:    {Description: description, Content: content},
+		"default": problemResponse(doc),
+	}
+}
+
+// problemResponse is the error response of every endpoint
+func problemResponse(doc *openapi.Document) *openapi.Response {
+	return &openapi.Response{
+		Description: "Problem details",
+		Content:     map[string]*openapi.MediaType{problem.ContentType: {Schema: doc.SchemaOf(problem.Problem{})}},
+	}
+}
@@ -2,66 +2,221 @@ package handler
 
 import (
 	"errors"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/BohdanKyryliuk/golang/money"
 	"github.com/BohdanKyryliuk/golang/worker"
 	"github.com/gin-gonic/gin"
 )
 
+// DefaultHeartbeat is how often an idle rate stream sends a heartbeat and a WebSocket
+// connection a ping
+const DefaultHeartbeat = 15 * time.Second
+
 // Rates holds the dependencies for rate-related HTTP handlers
 type Rates struct {
-	manager *worker.Manager
+	manager          *worker.Manager
+	heartbeat        time.Duration
+	maxSubscriptions int
+}
+
+// RatesOption is a functional option for configuring a Rates handler
+type RatesOption func(*Rates)
+
+// WithHeartbeat sets how often an idle rate stream sends a heartbeat and a WebSocket
+// connection a ping
+func WithHeartbeat(interval time.Duration) RatesOption {
+	return func(h *Rates) {
+		if interval > 0 {
+			h.heartbeat = interval
+		}
+	}
+}
+
+// WithMaxSubscriptions sets the number of currency pairs a WebSocket connection may subscribe to
+func WithMaxSubscriptions(limit int) RatesOption {
+	return func(h *Rates) {
+		if limit > 0 {
+			h.maxSubscriptions = limit
+		}
+	}
 }
 
 // NewRates creates a new Rates handler with the given worker manager
-func NewRates(manager *worker.Manager) *Rates {
-	return &Rates{manager: manager}
+func NewRates(manager *worker.Manager, opts ...RatesOption) *Rates {
+	h := &Rates{
+		manager:          manager,
+		heartbeat:        DefaultHeartbeat,
+		maxSubscriptions: DefaultMaxSubscriptions,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// rateSet renders cached rates by base currency, one CSV record per rate
+type rateSet map[string]*worker.RateData
+
+func (s rateSet) csvRecords() [][]string {
+	records := [][]string{{"base", "quote", "rate", "last_updated_at", "fetched_at", "derived", "stale"}}
+	for _, base := range slices.Sorted(maps.Keys(s)) {
+		data := s[base]
+		for _, quote := range slices.Sorted(maps.Keys(data.Rates)) {
+			records = append(records, []string{
+				base, quote, data.Rates[quote].Value.String(), data.LastUpdatedAt,
+				data.FetchedAt.Format(time.RFC3339), strconv.FormatBool(data.Derived), strconv.FormatBool(data.Stale),
+			})
+		}
+	}
+	return records
+}
+
+// rateData renders the cached rates of a base currency, one CSV record per rate
+type rateData struct {
+	*worker.RateData
+}
+
+func (d rateData) csvRecords() [][]string {
+	return rateSet{d.BaseCurrency: d.RateData}.csvRecords()
+}
+
+// history is the rate history of a currency pair, one CSV record per point
+type history struct {
+	Base     string                `json:"base"`
+	Quote    string                `json:"quote"`
+	Interval string                `json:"interval,omitempty"`
+	Points   []worker.HistoryPoint `json:"points"`
+}
+
+func (h history) csvRecords() [][]string {
+	records := [][]string{{"base", "quote", "time", "value", "open", "high", "low", "close", "samples"}}
+	for _, point := range h.Points {
+		records = append(records, []string{
+			h.Base, h.Quote, point.Time.Format(time.RFC3339), point.Value.String(),
+			optionalAmount(point.Open), optionalAmount(point.High), optionalAmount(point.Low), optionalAmount(point.Close),
+			strconv.Itoa(point.Samples),
+		})
+	}
+	return records
+}
+
+// optionalAmount formats an amount for CSV, leaving zero amounts of unset fields empty
+func optionalAmount(amount money.Amount) string {
+	if amount.IsZero() {
+		return ""
+	}
+	return amount.String()
+}
+
+// currencyList is the base currencies tracked by the workers
+type currencyList struct {
+	Currencies []string `json:"currencies"`
 }
 
 // GetRate handles requests for cached rates of a specific base currency
 // Query params: base (base currency, required)
 func (h *Rates) GetRate(c *gin.Context) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
-
 	baseCurrency := strings.ToUpper(c.Query("base"))
 	if baseCurrency == "" {
-		c.AbortWithStatusJSON(400, gin.H{"error": "base currency parameter is required"})
+		problem.Abort(c, problem.InvalidRequest("base currency parameter is required"))
 		return
 	}
 
-	rateData, err := h.manager.GetRates(baseCurrency)
+	data, err := h.manager.GetRates(baseCurrency)
 	if err != nil {
-		var notFoundErr *worker.NotFoundError
-		if errors.As(err, &notFoundErr) {
-			c.AbortWithStatusJSON(404, gin.H{"error": "rates not found for currency: " + baseCurrency})
-			return
-		}
-		c.AbortWithStatusJSON(500, gin.H{"error": "failed to get rates"})
+		problem.Abort(c, err)
 		return
 	}
 
-	c.JSON(200, rateData)
+	render(c, 200, rateData{data})
 }
 
 // GetAllRates handles requests for all cached rates
 func (h *Rates) GetAllRates(c *gin.Context) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
-
-	allRates := h.manager.GetAllRates()
-	c.JSON(200, allRates)
+	render(c, 200, rateSet(h.manager.GetAllRates()))
 }
 
-// GetWorkerStatus handles requests for worker status
+// GetWorkerStatus handles requests for worker status: the lifecycle state, the health
+// of every tracked currency and an overall healthy/degraded/failing verdict
 func (h *Rates) GetWorkerStatus(c *gin.Context) {
-	c.Header("Content-Type", "application/json; charset=utf-8")
+	render(c, 200, h.manager.GetWorkerStatus())
+}
+
+// GetHistory handles requests for the rate history of a currency pair
+// Query params: base and quote (required), from and to (RFC 3339, optional),
+// interval (duration such as 5m or 1h, optional), agg (last, avg or ohlc, default last)
+func (h *Rates) GetHistory(c *gin.Context) {
+	query := worker.HistoryQuery{
+		Base:        strings.ToUpper(c.Query("base")),
+		Quote:       strings.ToUpper(c.Query("quote")),
+		Aggregation: worker.Aggregation(strings.ToLower(c.Query("agg"))),
+	}
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if query.From, err = time.Parse(time.RFC3339, value); err != nil {
+			problem.Abort(c, problem.InvalidRequest("from must be an RFC 3339 time"))
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if query.To, err = time.Parse(time.RFC3339, value); err != nil {
+			problem.Abort(c, problem.InvalidRequest("to must be an RFC 3339 time"))
+			return
+		}
+	}
+	if value := c.Query("interval"); value != "" {
+		if query.Interval, err = time.ParseDuration(value); err != nil {
+			problem.Abort(c, problem.InvalidRequest("interval must be a duration such as 5m or 1h"))
+			return
+		}
+	}
+
+	points, err := h.manager.GetHistory(query)
+	if err != nil {
+		var notFoundErr *worker.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			err = problem.NotFound("no rate history for currency: " + query.Base)
+		}
+		problem.Abort(c, err)
+		return
+	}
 
-	status := struct {
-		Running    bool     `json:"running"`
-		Currencies []string `json:"currencies"`
-	}{
-		Running:    h.manager.IsRunning(),
-		Currencies: h.manager.GetCurrencies(),
+	response := history{
+		Base:   query.Base,
+		Quote:  query.Quote,
+		Points: points,
 	}
+	if query.Interval > 0 {
+		response.Interval = query.Interval.String()
+	}
+	render(c, 200, response)
+}
+
+// AddWorker handles requests to start tracking a base currency
+// Path params: currency (base currency code)
+func (h *Rates) AddWorker(c *gin.Context) {
+	currency := strings.ToUpper(c.Param("currency"))
+	if err := h.manager.AddCurrency(currency); err != nil {
+		problem.Abort(c, err)
+		return
+	}
+	render(c, 201, currencyList{Currencies: h.manager.GetCurrencies()})
+}
 
-	c.JSON(200, status)
+// RemoveWorker handles requests to stop tracking a base currency
+// Path params: currency (base currency code)
+func (h *Rates) RemoveWorker(c *gin.Context) {
+	currency := strings.ToUpper(c.Param("currency"))
+	if err := h.manager.RemoveCurrency(currency); err != nil {
+		problem.Abort(c, err)
+		return
+	}
+	render(c, 200, currencyList{Currencies: h.manager.GetCurrencies()})
 }
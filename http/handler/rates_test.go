@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// newRatesRouter serves the rates handlers from a started worker manager
+func newRatesRouter(t *testing.T, opts ...RatesOption) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := worker.NewManager(fake, worker.Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Stop(context.Background()) })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(manager.GetAllRates()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for rates")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	h := NewRates(manager, append([]RatesOption{WithHeartbeat(20 * time.Millisecond)}, opts...)...)
+	router := gin.New()
+	router.GET("/rates", h.GetRate)
+	router.GET("/rates/all", h.GetAllRates)
+	router.GET("/rates/status", h.GetWorkerStatus)
+	router.GET("/rates/history", h.GetHistory)
+	router.GET("/rates/stream", h.Stream)
+	router.GET("/ws", h.WebSocket)
+	router.POST("/rates/workers/:currency", h.AddWorker)
+	router.DELETE("/rates/workers/:currency", h.RemoveWorker)
+	return router
+}
+
+func TestRatesHistory(t *testing.T) {
+	router := newRatesRouter(t)
+
+	w := serve(router, "/rates/history?base=usd&quote=eur&interval=1h&agg=ohlc")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+
+	var response struct {
+		Base     string                `json:"base"`
+		Quote    string                `json:"quote"`
+		Interval string                `json:"interval"`
+		Points   []worker.HistoryPoint `json:"points"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if response.Base != "USD" || response.Quote != "EUR" || response.Interval != "1h0m0s" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+	if len(response.Points) != 1 || response.Points[0].Close.String() != "0.92" || response.Points[0].Samples != 1 {
+		t.Errorf("Unexpected points: %+v", response.Points)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{name: "missing quote", target: "/rates/history?base=USD", want: http.StatusBadRequest},
+		{name: "malformed from", target: "/rates/history?base=USD&quote=EUR&from=yesterday", want: http.StatusBadRequest},
+		{name: "malformed interval", target: "/rates/history?base=USD&quote=EUR&interval=hourly", want: http.StatusBadRequest},
+		{name: "unknown aggregation", target: "/rates/history?base=USD&quote=EUR&agg=median", want: http.StatusBadRequest},
+		{name: "future range", target: "/rates/history?base=USD&quote=EUR&from=2999-01-01T00:00:00Z", want: http.StatusOK},
+		{name: "unknown base", target: "/rates/history?base=GBP&quote=EUR", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(router, tt.target); w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestRatesWorkers(t *testing.T) {
+	router := newRatesRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{name: "add", method: http.MethodPost, target: "/rates/workers/jpy", want: http.StatusCreated},
+		{name: "add tracked", method: http.MethodPost, target: "/rates/workers/JPY", want: http.StatusConflict},
+		{name: "add invalid", method: http.MethodPost, target: "/rates/workers/YEN1", want: http.StatusBadRequest},
+		{name: "remove", method: http.MethodDelete, target: "/rates/workers/USD", want: http.StatusOK},
+		{name: "remove untracked", method: http.MethodDelete, target: "/rates/workers/USD", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+			if w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rates/workers/CHF", nil))
+	var response struct {
+		Currencies []string `json:"currencies"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if len(response.Currencies) != 2 || response.Currencies[0] != "JPY" || response.Currencies[1] != "CHF" {
+		t.Errorf("Expected the live currencies [JPY CHF], got %v", response.Currencies)
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Media types the handlers respond with
+const (
+	mimeJSON    = "application/json"
+	mimeXML     = "application/xml"
+	mimeTextXML = "text/xml"
+	mimeCSV     = "text/csv"
+)
+
+// xmlRoot is the root element of XML responses
+const xmlRoot = "response"
+
+// csvTable is implemented by payloads that can be rendered as CSV
+type csvTable interface {
+	// csvRecords returns the header followed by one record per rate
+	csvRecords() [][]string
+}
+
+// render writes payload with status in the format negotiated from the Accept header:
+// JSON (the default), XML or, for payloads implementing csvTable, CSV. JSON and XML
+// are indented with ?pretty=1. Requests accepting none of them get a 406 problem.
+func render(c *gin.Context, status int, payload any) {
+	offers := []string{mimeJSON, mimeXML, mimeTextXML}
+	if _, ok := payload.(csvTable); ok {
+		offers = append(offers, mimeCSV)
+	}
+
+	c.Header("Vary", "Accept")
+	format := c.NegotiateFormat(offers...)
+	if format == "" {
+		problem.Abort(c, problem.New(problem.TypeNotAcceptable, 406, "supported formats: "+strings.Join(offers, ", ")))
+		return
+	}
+	write(c, status, format, payload)
+}
+
+// write encodes payload in format
+func write(c *gin.Context, status int, format string, payload any) {
+	pretty, _ := strconv.ParseBool(c.Query("pretty"))
+
+	switch format {
+	case mimeXML, mimeTextXML:
+		body, err := marshalXML(payload, pretty)
+		if err != nil {
+			problem.Abort(c, fmt.Errorf("encoding XML response: %w", err))
+			return
+		}
+		c.Data(status, format+"; charset=utf-8", body)
+	case mimeCSV:
+		var body bytes.Buffer
+		if err := csv.NewWriter(&body).WriteAll(payload.(csvTable).csvRecords()); err != nil {
+			problem.Abort(c, fmt.Errorf("encoding CSV response: %w", err))
+			return
+		}
+		c.Data(status, mimeCSV+"; charset=utf-8", body.Bytes())
+	default:
+		if pretty {
+			c.IndentedJSON(status, payload)
+			return
+		}
+		c.JSON(status, payload)
+	}
+}
+
+// marshalXML encodes payload as XML with the same shape as its JSON encoding: objects
+// become elements named after their keys and array items become <item> elements
+func marshalXML(payload any, pretty bool) ([]byte, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber() // Keeps the precision of amounts
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&body)
+	if pretty {
+		encoder.Indent("", "  ")
+	}
+	if err := encodeXML(encoder, xmlRoot, value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// encodeXML writes a decoded JSON value as an element; keys that aren't valid element
+// names are written as <entry key="...">
+func encodeXML(encoder *xml.Encoder, name string, value any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !isXMLName(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if err := encodeXML(encoder, key, v[key]); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	case []any:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := encodeXML(encoder, "item", item); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	case nil:
+		return encoder.EncodeElement("", start)
+	default:
+		return encoder.EncodeElement(fmt.Sprint(v), start)
+	}
+}
+
+// isXMLName reports whether name can be used as an element name as is
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
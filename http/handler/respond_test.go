@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	router := newRatesRouter(t)
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		wantStatus  int
+		contentType string
+		contains    string
+	}{
+		{name: "json by default", target: "/rates?base=usd", wantStatus: 200, contentType: "application/json", contains: `"base_currency":"USD"`},
+		{name: "any type", target: "/rates?base=usd", accept: "*/*", wantStatus: 200, contentType: "application/json", contains: `"base_currency":"USD"`},
+		{name: "pretty json", target: "/rates?base=usd&pretty=1", wantStatus: 200, contentType: "application/json", contains: "\n    \"base_currency\": \"USD\""},
+		{name: "xml", target: "/rates?base=usd", accept: "application/xml", wantStatus: 200, contentType: "application/xml", contains: "<base_currency>USD</base_currency>"},
+		{name: "text xml", target: "/rates/status", accept: "text/xml", wantStatus: 200, contentType: "text/xml", contains: "<status>"},
+		{name: "pretty xml", target: "/rates?base=usd&pretty=true", accept: "application/xml", wantStatus: 200, contentType: "application/xml", contains: "\n  <base_currency>USD</base_currency>"},
+		{name: "preferred type", target: "/rates?base=usd", accept: "text/csv, application/json", wantStatus: 200, contentType: "text/csv", contains: "base,quote,rate"},
+		{name: "csv unsupported", target: "/rates/status", accept: "text/csv", wantStatus: 406, contentType: "application/problem+json", contains: `"type":"/problems/not-acceptable"`},
+		{name: "csv fallback", target: "/rates/status", accept: "text/csv, application/json", wantStatus: 200, contentType: "application/json", contains: `"status":`},
+		{name: "unsupported type", target: "/rates?base=usd", accept: "image/png", wantStatus: 406, contentType: "application/problem+json", contains: `"type":"/problems/not-acceptable"`},
+		{name: "xml error", target: "/rates?base=xyz", accept: "application/xml", wantStatus: 404, contentType: "application/problem+json", contains: `"type":"/problems/not-found"`},
+		{name: "csv error", target: "/rates?base=xyz", accept: "text/csv", wantStatus: 404, contentType: "application/problem+json", contains: `"type":"/problems/not-found"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", got, tt.contentType)
+			}
+			if !strings.Contains(w.Body.String(), tt.contains) {
+				t.Errorf("Body doesn't contain %q: %s", tt.contains, w.Body)
+			}
+		})
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	router := newRatesRouter(t)
+
+	tests := []struct {
+		name      string
+		target    string
+		header    string
+		wantQuote string // Of the first record
+	}{
+		{name: "rates", target: "/rates?base=usd", header: "base,quote,rate,last_updated_at,fetched_at,derived,stale", wantQuote: "CAD"},
+		{name: "all rates", target: "/rates/all", header: "base,quote,rate,last_updated_at,fetched_at,derived,stale", wantQuote: "CAD"},
+		{name: "history", target: "/rates/history?base=usd&quote=eur", header: "base,quote,time,value,open,high,low,close,samples", wantQuote: "EUR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", "text/csv")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+			}
+
+			records, err := csv.NewReader(w.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("Invalid CSV response: %v", err)
+			}
+			if got := strings.Join(records[0], ","); got != tt.header {
+				t.Errorf("Header = %s, want %s", got, tt.header)
+			}
+			if len(records) < 2 {
+				t.Fatalf("Expected records, got %v", records)
+			}
+			if records[1][0] != "USD" || records[1][1] != tt.wantQuote {
+				t.Errorf("Unexpected first record: %v", records[1])
+			}
+		})
+	}
+}
+
+func TestMarshalXML(t *testing.T) {
+	payload := map[string]any{
+		"rates":  map[string]string{"EUR": "0.9", "1st": "x"},
+		"codes":  []string{"EUR", "GBP"},
+		"amount": 1.5,
+		"none":   nil,
+	}
+	got, err := marshalXML(payload, false)
+	if err != nil {
+		t.Fatalf("marshalXML() error = %v", err)
+	}
+
+	want := xml.Header + "<response><amount>1.5</amount><codes><item>EUR</item><item>GBP</item></codes>" +
+		`<none></none><rates><entry key="1st">x</entry><EUR>0.9</EUR></rates></response>`
+	if string(got) != want {
+		t.Errorf("marshalXML() = %s, want %s", got, want)
+	}
+}
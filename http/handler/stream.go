@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// streamRetry is the reconnection delay suggested to Server-Sent Events clients
+const streamRetry = 3 * time.Second
+
+// Stream handles requests for a Server-Sent Events stream of rate updates. Every time
+// a worker stores fresh rates a "rates" event with the changes is sent; a comment is
+// sent as heartbeat while idle. The stream ends when the client disconnects or the
+// server shuts down.
+// Query params: base (base currency, optional), currencies (comma-separated quote currencies, optional)
+// Headers: Last-Event-ID (resumes after that event from the recent events, optional)
+func (h *Rates) Stream(c *gin.Context) {
+	filter := worker.EventFilter{
+		Base:   strings.ToUpper(c.Query("base")),
+		Quotes: splitCurrencies(c.Query("currencies")),
+	}
+
+	var opts []worker.SubscribeOption
+	if value := c.GetHeader("Last-Event-ID"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			problem.Abort(c, problem.InvalidRequest("Last-Event-ID must be an event id"))
+			return
+		}
+		opts = append(opts, worker.WithLastEventID(id))
+	}
+
+	sub := h.manager.Subscribe(filter, opts...)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(200)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := writeEvent(c, event); err != nil {
+				log.Printf("Error writing rate event: %v", err)
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes a rate event in the Server-Sent Events format
+func writeEvent(c *gin.Context, event worker.RateUpdated) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: rates\ndata: %s\n\n", event.ID, data)
+	return err
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/worker"
+)
+
+// sseMessage is a message of a Server-Sent Events stream
+type sseMessage struct {
+	fields  map[string]string
+	comment string
+}
+
+// readMessage reads the next message of a Server-Sent Events stream
+func readMessage(t *testing.T, reader *bufio.Reader) sseMessage {
+	t.Helper()
+	msg := sseMessage{fields: make(map[string]string)}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Reading stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return msg
+		}
+		if comment, ok := strings.CutPrefix(line, ":"); ok {
+			msg.comment = strings.TrimSpace(comment)
+			continue
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		msg.fields[name] = value
+	}
+}
+
+// openStream requests a rate stream that is closed at the end of the test
+func openStream(t *testing.T, server *httptest.Server, target, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+target, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", target, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestRatesStream(t *testing.T) {
+	router := newRatesRouter(t)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	resp, reader := openStream(t, server, "/rates/stream?currencies=eur", "0")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if msg := readMessage(t, reader); msg.fields["retry"] != "3000" {
+		t.Errorf("Expected a retry delay first, got %+v", msg)
+	}
+
+	// The first fetch happened before connecting and is replayed
+	msg := readMessage(t, reader)
+	var event worker.RateUpdated
+	if err := json.Unmarshal([]byte(msg.fields["data"]), &event); err != nil {
+		t.Fatalf("Invalid event data %q: %v", msg.fields["data"], err)
+	}
+	if msg.fields["id"] != "1" || msg.fields["event"] != "rates" || event.Base != "USD" || len(event.Changes) != 1 {
+		t.Errorf("Unexpected replayed event: %+v %+v", msg, event)
+	}
+	if msg := readMessage(t, reader); msg.comment != "heartbeat" {
+		t.Errorf("Expected a heartbeat while idle, got %+v", msg)
+	}
+
+	// Tracking another currency publishes its first fetch
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rates/workers/GBP", nil))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		msg := readMessage(t, reader)
+		if msg.comment == "" {
+			if msg.fields["id"] != "2" || !strings.Contains(msg.fields["data"], `"base":"GBP"`) {
+				t.Errorf("Unexpected live event: %+v", msg)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for a live event")
+		}
+	}
+}
+
+func TestRatesStreamInvalidLastEventID(t *testing.T) {
+	router := newRatesRouter(t)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	if resp, _ := openStream(t, server, "/rates/stream", "latest"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", resp.StatusCode)
+	}
+}
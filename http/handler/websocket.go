@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultMaxSubscriptions is the number of currency pairs a WebSocket connection may subscribe to
+const DefaultMaxSubscriptions = 50
+
+const (
+	// wsReadLimit is the maximum size of a message from a WebSocket client
+	wsReadLimit = 4096
+	// wsWriteTimeout is how long writing a message to a WebSocket client may take
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WebSocket message types
+const (
+	wsSubscribe    = "subscribe"
+	wsUnsubscribe  = "unsubscribe"
+	wsSubscribed   = "subscribed"
+	wsUnsubscribed = "unsubscribed"
+	wsRates        = "rates"
+	wsError        = "error"
+)
+
+// WebSocket error codes
+const (
+	wsInvalidMessage       = "invalid_message"
+	wsInvalidPair          = "invalid_pair"
+	wsTooManySubscriptions = "too_many_subscriptions"
+	wsNotSubscribed        = "not_subscribed"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// wsRequest is a message from a WebSocket client
+type wsRequest struct {
+	Type  string `json:"type"`
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+
+	invalid bool // Set if the message isn't a JSON object
+}
+
+// wsResponse is a message to a WebSocket client
+type wsResponse struct {
+	Type          string              `json:"type"`
+	Base          string              `json:"base,omitempty"`
+	Quote         string              `json:"quote,omitempty"`
+	Subscriptions *int                `json:"subscriptions,omitempty"`
+	Event         *worker.RateUpdated `json:"event,omitempty"`
+	Code          string              `json:"code,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// wsPair is a currency pair subscribed to by a WebSocket client
+type wsPair struct {
+	base, quote string
+}
+
+// WebSocket handles WebSocket connections subscribing to rate updates. Clients send
+// {"type":"subscribe","base":"USD","quote":"EUR"} or "unsubscribe" messages and receive
+// "rates" messages with the changes of their pairs whenever a worker stores fresh
+// rates. Invalid requests are answered with "error" messages; the connection stays open.
+func (h *Rates) WebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already replied with an error
+		return
+	}
+	defer conn.Close()
+
+	sub := h.manager.Subscribe(worker.EventFilter{})
+	defer sub.Close()
+
+	// Clients must answer pings in time, a missing pong ends the connection
+	pongWait := 2 * h.heartbeat
+	conn.SetReadLimit(wsReadLimit)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	// Only this goroutine writes to the connection, the reader hands requests over
+	// and stops when the client goes away or misses a pong
+	requests := make(chan wsRequest)
+	go func() {
+		defer close(requests)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var request wsRequest
+			if err := json.Unmarshal(message, &request); err != nil {
+				request = wsRequest{invalid: true}
+			}
+			requests <- request
+		}
+	}()
+	defer func() {
+		// Unblock the reader if it is still running
+		conn.Close()
+		for range requests {
+		}
+	}()
+
+	ping := time.NewTicker(h.heartbeat)
+	defer ping.Stop()
+
+	pairs := make(map[wsPair]bool)
+	ctx := c.Request.Context()
+	for {
+		var response *wsResponse
+		select {
+		case <-ctx.Done():
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(wsWriteTimeout))
+			return
+		case request, ok := <-requests:
+			if !ok {
+				return
+			}
+			response = h.handleWSRequest(pairs, request)
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			response = pairEvent(pairs, event)
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+
+		if response == nil {
+			continue
+		}
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := conn.WriteJSON(response); err != nil {
+			return
+		}
+	}
+}
+
+// handleWSRequest applies a subscribe or unsubscribe request and returns the reply
+func (h *Rates) handleWSRequest(pairs map[wsPair]bool, request wsRequest) *wsResponse {
+	pair := wsPair{base: strings.ToUpper(request.Base), quote: strings.ToUpper(request.Quote)}
+	if request.invalid {
+		return wsErrorResponse(wsInvalidMessage, "messages must be JSON objects")
+	}
+	if request.Type != wsSubscribe && request.Type != wsUnsubscribe {
+		return wsErrorResponse(wsInvalidMessage, fmt.Sprintf("unknown message type %q", request.Type))
+	}
+	if !worker.IsCurrencyCode(pair.base) || !worker.IsCurrencyCode(pair.quote) {
+		return wsErrorResponse(wsInvalidPair, "base and quote must be 3-letter currency codes")
+	}
+
+	response := &wsResponse{Base: pair.base, Quote: pair.quote}
+	switch {
+	case request.Type == wsUnsubscribe:
+		if !pairs[pair] {
+			return wsErrorResponse(wsNotSubscribed, "not subscribed to "+pair.base+"/"+pair.quote)
+		}
+		delete(pairs, pair)
+		response.Type = wsUnsubscribed
+	case !pairs[pair] && len(pairs) >= h.maxSubscriptions:
+		return wsErrorResponse(wsTooManySubscriptions, fmt.Sprintf("at most %d subscriptions per connection", h.maxSubscriptions))
+	default:
+		pairs[pair] = true
+		response.Type = wsSubscribed
+	}
+	count := len(pairs)
+	response.Subscriptions = &count
+	return response
+}
+
+// pairEvent returns the message with the changes of the subscribed pairs, or nil if there are none
+func pairEvent(pairs map[wsPair]bool, event worker.RateUpdated) *wsResponse {
+	changes := make(map[string]worker.RateChange)
+	for quote, change := range event.Changes {
+		if pairs[wsPair{base: event.Base, quote: quote}] {
+			changes[quote] = change
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	event.Changes = changes
+	return &wsResponse{Type: wsRates, Event: &event}
+}
+
+func wsErrorResponse(code, message string) *wsResponse {
+	return &wsResponse{Type: wsError, Code: code, Error: message}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialRates opens a WebSocket connection that is closed at the end of the test
+func dialRates(t *testing.T, server *httptest.Server) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// exchange sends a message and returns the reply
+func exchange(t *testing.T, conn *websocket.Conn, message string) wsResponse {
+	t.Helper()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	return readResponse(t, conn)
+}
+
+func readResponse(t *testing.T, conn *websocket.Conn) wsResponse {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var response wsResponse
+	if err := conn.ReadJSON(&response); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return response
+}
+
+func TestRatesWebSocket(t *testing.T) {
+	router := newRatesRouter(t, WithHeartbeat(time.Second), WithMaxSubscriptions(2))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	conn := dialRates(t, server)
+
+	tests := []struct {
+		name    string
+		message string
+		want    wsResponse
+	}{
+		{name: "subscribe", message: `{"type":"subscribe","base":"gbp","quote":"eur"}`, want: wsResponse{Type: "subscribed", Base: "GBP", Quote: "EUR"}},
+		{name: "subscribe again", message: `{"type":"subscribe","base":"GBP","quote":"EUR"}`, want: wsResponse{Type: "subscribed", Base: "GBP", Quote: "EUR"}},
+		{name: "second pair", message: `{"type":"subscribe","base":"USD","quote":"JPY"}`, want: wsResponse{Type: "subscribed", Base: "USD", Quote: "JPY"}},
+		{name: "too many", message: `{"type":"subscribe","base":"USD","quote":"CHF"}`, want: wsResponse{Type: "error", Code: "too_many_subscriptions"}},
+		{name: "unsubscribe", message: `{"type":"unsubscribe","base":"USD","quote":"JPY"}`, want: wsResponse{Type: "unsubscribed", Base: "USD", Quote: "JPY"}},
+		{name: "not subscribed", message: `{"type":"unsubscribe","base":"USD","quote":"JPY"}`, want: wsResponse{Type: "error", Code: "not_subscribed"}},
+		{name: "invalid pair", message: `{"type":"subscribe","base":"US","quote":"EUR"}`, want: wsResponse{Type: "error", Code: "invalid_pair"}},
+		{name: "unknown type", message: `{"type":"publish"}`, want: wsResponse{Type: "error", Code: "invalid_message"}},
+		{name: "not json", message: `subscribe GBP/EUR`, want: wsResponse{Type: "error", Code: "invalid_message"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exchange(t, conn, tt.message)
+			if got.Type != tt.want.Type || got.Base != tt.want.Base || got.Quote != tt.want.Quote || got.Code != tt.want.Code {
+				t.Errorf("Reply = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Tracking GBP publishes its first fetch to the GBP/EUR subscription
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rates/workers/GBP", nil))
+	response := readResponse(t, conn)
+	if response.Type != "rates" || response.Event == nil || response.Event.Base != "GBP" || len(response.Event.Changes) != 1 {
+		t.Fatalf("Expected GBP/EUR rates, got %+v", response)
+	}
+	if change := response.Event.Changes["EUR"]; change.New.IsZero() {
+		t.Errorf("Expected the EUR rate, got %+v", change)
+	}
+}
+
+func TestRatesWebSocketPing(t *testing.T) {
+	server := httptest.NewServer(newRatesRouter(t, WithHeartbeat(50*time.Millisecond)))
+	t.Cleanup(server.Close)
+	conn := dialRates(t, server)
+
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	responses := make(chan wsResponse, 1)
+	go func() {
+		defer close(responses)
+		for {
+			var response wsResponse
+			if err := conn.ReadJSON(&response); err != nil {
+				return
+			}
+			responses <- response
+		}
+	}()
+
+	// Answering pings keeps the connection open past the pong deadline
+	for range 4 {
+		select {
+		case <-pings:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for a ping")
+		}
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"subscribe","base":"USD","quote":"EUR"}`)); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	select {
+	case response := <-responses:
+		if response.Type != "subscribed" {
+			t.Errorf("Expected the connection to stay open, got %+v", response)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a reply")
+	}
+}
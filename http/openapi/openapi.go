@@ -0,0 +1,180 @@
+// Package openapi models OpenAPI 3 documents and derives their schemas from Go types,
+// so that the documented responses follow the JSON encoding of the handlers.
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Version is the OpenAPI version of the documents
+const Version = "3.0.3"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	schemas *schemaGenerator
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lower case HTTP method
+type PathItem map[string]*Operation
+
+// Operation is an API operation on a path
+type Operation struct {
+	Summary     string               `json:"summary"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	// Security lists the alternative requirements, any of which admits a request
+	Security []SecurityRequirement `json:"security,omitempty"`
+}
+
+// SecurityRequirement maps the names of security schemes to the scopes they need
+type SecurityRequirement map[string][]string
+
+// Parameter is a path, query or header parameter of an operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of a request by media type
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is a response of an operation, with its body by media type
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas and security schemes referenced by the operations
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a way of authenticating requests
+type SecurityScheme struct {
+	Type        string `json:"type"` // apiKey or http
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty"`   // Header, query or cookie name of an apiKey scheme
+	In          string `json:"in,omitempty"`     // Location of an apiKey scheme
+	Scheme      string `json:"scheme,omitempty"` // Authorization scheme of an http scheme, e.g. bearer
+}
+
+// Schema is a JSON schema as used by OpenAPI
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// New creates an empty document
+func New(info Info) *Document {
+	d := &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+	d.schemas = newSchemaGenerator(d.Components.Schemas)
+	return d
+}
+
+// Add adds an operation; Gin path parameters such as :id are converted to {id}
+func (d *Document) Add(method, path string, op *Operation) {
+	path = Path(path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// Operation returns the operation on a path, given in Gin or OpenAPI syntax
+func (d *Document) Operation(method, path string) (*Operation, bool) {
+	item, ok := d.Paths[Path(path)]
+	if !ok {
+		return nil, false
+	}
+	op, ok := (*item)[strings.ToLower(method)]
+	return op, ok
+}
+
+// Define sets the schema of the Go type of v instead of deriving it, for types with
+// a custom JSON encoding
+func (d *Document) Define(v any, schema *Schema) {
+	d.schemas.define(v, schema)
+}
+
+// SchemaOf returns the schema of the JSON encoding of v. Named struct types are added to
+// the components and referenced.
+func (d *Document) SchemaOf(v any) *Schema {
+	return d.schemas.schemaOf(v)
+}
+
+// JSON returns the content of a JSON body with the schema of v
+func (d *Document) JSON(v any) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: d.SchemaOf(v)}}
+}
+
+// ginParam matches the path parameters of Gin routes
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Path converts a Gin route path to an OpenAPI path
+func Path(ginPath string) string {
+	return ginParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// QueryParam creates a query parameter with a string schema
+func QueryParam(name, description string, required bool) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Required: required, Schema: &Schema{Type: "string"}}
+}
+
+// PathParam creates a path parameter with a string schema
+func PathParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// HeaderParam creates an optional header parameter with a string schema
+func HeaderParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
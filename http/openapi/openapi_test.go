@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) { return []byte("high"), nil }
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type node struct {
+	base
+	Name     string            `json:"name"`
+	Note     string            `json:"note,omitempty"`
+	Level    level             `json:"level"`
+	Children []*node           `json:"children,omitzero"`
+	Labels   map[string]string `json:"labels"`
+	Created  time.Time         `json:"created"`
+	Secret   string            `json:"-"`
+	internal int
+}
+
+func TestSchemaOf(t *testing.T) {
+	doc := New(Info{Title: "Test", Version: "1"})
+
+	ref := doc.SchemaOf(&node{})
+	if ref.Ref != "#/components/schemas/Node" {
+		t.Fatalf("SchemaOf() = %+v, want a reference to Node", ref)
+	}
+
+	schema := doc.Components.Schemas["Node"]
+	want := map[string]string{
+		"id":       `{"type":"string"}`,
+		"name":     `{"type":"string"}`,
+		"note":     `{"type":"string"}`,
+		"level":    `{"type":"string"}`,
+		"children": `{"type":"array","items":{"$ref":"#/components/schemas/Node"}}`,
+		"labels":   `{"type":"object","additionalProperties":{"type":"string"}}`,
+		"created":  `{"type":"string","format":"date-time"}`,
+	}
+	if len(schema.Properties) != len(want) {
+		t.Errorf("Properties = %v, want %d", schema.Properties, len(want))
+	}
+	for name, wantJSON := range want {
+		got, _ := json.Marshal(schema.Properties[name])
+		if string(got) != wantJSON {
+			t.Errorf("Property %s = %s, want %s", name, got, wantJSON)
+		}
+	}
+	if wantRequired := []string{"id", "name", "level", "labels", "created"}; !slices.Equal(schema.Required, wantRequired) {
+		t.Errorf("Required = %v, want %v", schema.Required, wantRequired)
+	}
+
+	doc.Define(level(0), &Schema{Type: "integer"})
+	if got := doc.SchemaOf(level(0)); got.Type != "integer" {
+		t.Errorf("SchemaOf() of a defined type = %+v", got)
+	}
+}
+
+func TestPath(t *testing.T) {
+	tests := map[string]string{
+		"/rates":                    "/rates",
+		"/rates/workers/:currency":  "/rates/workers/{currency}",
+		"/alerts/:id/history/*path": "/alerts/{id}/history/{path}",
+	}
+	for ginPath, want := range tests {
+		if got := Path(ginPath); got != want {
+			t.Errorf("Path(%q) = %q, want %q", ginPath, got, want)
+		}
+	}
+
+	doc := New(Info{Title: "Test", Version: "1"})
+	doc.Add("DELETE", "/alerts/:id", &Operation{Summary: "Remove"})
+	if op, ok := doc.Operation("DELETE", "/alerts/{id}"); !ok || op.Summary != "Remove" {
+		t.Errorf("Operation() = %v, %v", op, ok)
+	}
+	if _, ok := doc.Operation("GET", "/alerts/:id"); ok {
+		t.Error("Operation() found an operation that wasn't added")
+	}
+}
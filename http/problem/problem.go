@@ -0,0 +1,211 @@
+// Package problem implements RFC 7807 problem details, the body of every error
+// response of the HTTP API, and maps the errors of the other packages to them.
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/alert"
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/http/requestid"
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// Problem types, stable identifiers clients can switch on
+const (
+	TypeInvalidRequest      = "/problems/invalid-request"
+	TypeUnauthorized        = "/problems/unauthorized"
+	TypeForbidden           = "/problems/forbidden"
+	TypeBudgetExceeded      = "/problems/budget-exceeded"
+	TypeNotFound            = "/problems/not-found"
+	TypeConflict            = "/problems/conflict"
+	TypeNotAcceptable       = "/problems/not-acceptable"
+	TypeRateLimited         = "/problems/rate-limited"
+	TypeQuotaExceeded       = "/problems/quota-exceeded"
+	TypeUpstreamUnavailable = "/problems/upstream-unavailable"
+	TypeConfiguration       = "/problems/configuration-error"
+	TypeInternal            = "/problems/internal-error"
+)
+
+// titles are the short summaries of the problem types, the same for every occurrence
+var titles = map[string]string{
+	TypeInvalidRequest:      "Invalid request",
+	TypeUnauthorized:        "Unauthorized",
+	TypeForbidden:           "Forbidden",
+	TypeBudgetExceeded:      "Request budget exceeded",
+	TypeNotFound:            "Not found",
+	TypeConflict:            "Conflict",
+	TypeNotAcceptable:       "Not acceptable",
+	TypeRateLimited:         "Rate limited",
+	TypeQuotaExceeded:       "Quota exceeded",
+	TypeUpstreamUnavailable: "Upstream unavailable",
+	TypeConfiguration:       "Service configuration error",
+	TypeInternal:            "Internal error",
+}
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance identifies the request the problem occurred in
+	Instance string `json:"instance,omitempty"`
+	// RetryAfter is the number of seconds to wait before retrying, also sent as the Retry-After header
+	RetryAfter int `json:"retry_after,omitempty"`
+	// RequiredScope is the API key scope the request lacks
+	RequiredScope string `json:"required_scope,omitempty"`
+	// UpstreamCode is the error code of the currency data provider
+	UpstreamCode string `json:"upstream_code,omitempty"`
+
+	err error // Cause, logged for server errors
+}
+
+// New creates a problem of a type
+func New(typ string, status int, detail string) *Problem {
+	return &Problem{Type: typ, Title: titles[typ], Status: status, Detail: detail}
+}
+
+// InvalidRequest creates a 400 problem
+func InvalidRequest(detail string) *Problem {
+	return New(TypeInvalidRequest, 400, detail)
+}
+
+// NotFound creates a 404 problem
+func NotFound(detail string) *Problem {
+	return New(TypeNotFound, 404, detail)
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return fmt.Sprintf("%d %s", p.Status, p.Title)
+	}
+	return fmt.Sprintf("%d %s: %s", p.Status, p.Title, p.Detail)
+}
+
+func (p *Problem) Unwrap() error {
+	return p.err
+}
+
+// FromError maps an error to a problem. Problems are returned as is, errors of the
+// currency API, the workers and the alert rules get their matching type, and anything
+// else is an internal error.
+func FromError(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+	p = fromError(err)
+	p.err = err
+	return p
+}
+
+func fromError(err error) *Problem {
+	// Invalid input, rejected either locally or by the provider
+	var validationErr *currencyapi.ValidationError
+	if errors.As(err, &validationErr) {
+		return InvalidRequest(validationErr.Field + ": " + validationErr.Message)
+	}
+
+	var apiErr *currencyapi.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == 422:
+			return withUpstream(InvalidRequest(apiErr.Message), apiErr.Code, apiErr.RetryAfter)
+		case apiErr.IsInvalidAPIKey():
+			return New(TypeConfiguration, 500, "the currency data provider rejected the API key")
+		case apiErr.IsQuotaExceeded():
+			return withUpstream(New(TypeQuotaExceeded, 503, "the request quota of the currency data provider is exhausted"),
+				apiErr.Code, apiErr.RetryAfter)
+		case apiErr.StatusCode == 429:
+			code := apiErr.Code
+			if code == "" {
+				code = strconv.Itoa(apiErr.StatusCode)
+			}
+			return withUpstream(rateLimited(), code, apiErr.RetryAfter)
+		}
+	}
+
+	var reservedErr *currencyapi.QuotaReservedError
+	if errors.As(err, &reservedErr) {
+		return New(TypeQuotaExceeded, 503, "the remaining request quota is reserved for background updates")
+	}
+
+	var httpErr *currencyapi.HTTPError
+	if errors.As(err, &httpErr) && httpErr.IsRateLimited() {
+		return withUpstream(rateLimited(), strconv.Itoa(httpErr.StatusCode), httpErr.RetryAfter)
+	}
+
+	if currencyapi.IsTemporaryError(err) {
+		p := New(TypeUpstreamUnavailable, 503, "the currency data provider is temporarily unavailable")
+		retryAfter, _ := currencyapi.GetRetryAfter(err)
+		if status, ok := currencyapi.GetHTTPStatusCode(err); ok {
+			return withUpstream(p, strconv.Itoa(status), retryAfter)
+		}
+		return withUpstream(p, "", retryAfter)
+	}
+
+	var notFoundErr *worker.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return NotFound(notFoundErr.Error())
+	}
+	var queryErr *worker.QueryError
+	if errors.As(err, &queryErr) {
+		return InvalidRequest(queryErr.Field + ": " + queryErr.Message)
+	}
+	var ruleErr *alert.ValidationError
+	if errors.As(err, &ruleErr) {
+		return InvalidRequest(ruleErr.Error())
+	}
+	switch {
+	case errors.Is(err, worker.ErrInvalidCurrency):
+		return InvalidRequest(err.Error())
+	case errors.Is(err, worker.ErrNotTracked), errors.Is(err, alert.ErrRuleNotFound):
+		return NotFound(err.Error())
+	case errors.Is(err, worker.ErrAlreadyTracked), errors.Is(err, worker.ErrPivotCurrency), errors.Is(err, alert.ErrRuleExists):
+		return New(TypeConflict, 409, err.Error())
+	}
+
+	return New(TypeInternal, 500, "")
+}
+
+// rateLimited is the problem of requests the currency data provider rate limited
+func rateLimited() *Problem {
+	return New(TypeRateLimited, 429, "the currency data provider is rate limiting requests")
+}
+
+// withUpstream sets the provider error code and the delay it asked for
+func withUpstream(p *Problem, code string, retryAfter time.Duration) *Problem {
+	p.UpstreamCode = code
+	if retryAfter > 0 {
+		p.RetryAfter = int(math.Ceil(retryAfter.Seconds()))
+	}
+	return p
+}
+
+// Abort aborts the request with the problem err maps to
+func Abort(c *gin.Context, err error) {
+	p := *FromError(err)
+	if p.Status >= 500 {
+		log.Printf("Error handling %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	if id := requestid.Get(c); id != "" {
+		p.Instance = "urn:request-id:" + id
+	}
+	if p.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(p.RetryAfter))
+	}
+
+	// Set before rendering, which keeps an existing content type
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, &p)
+}
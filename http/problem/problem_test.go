@@ -0,0 +1,100 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/alert"
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/http/requestid"
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantType     string
+		wantStatus   int
+		retryAfter   int
+		upstreamCode string
+	}{
+		{name: "problem", err: fmt.Errorf("wrapped: %w", New(TypeConflict, 409, "taken")), wantType: TypeConflict, wantStatus: 409},
+		{name: "validation", err: &currencyapi.ValidationError{Field: "date", Message: "must be in the past"}, wantType: TypeInvalidRequest, wantStatus: 400},
+		{name: "provider validation", err: &currencyapi.APIError{StatusCode: 422, Code: "validation_error"}, wantType: TypeInvalidRequest, wantStatus: 400, upstreamCode: "validation_error"},
+		{name: "invalid key", err: &currencyapi.APIError{StatusCode: 401, Code: "invalid_api_key"}, wantType: TypeConfiguration, wantStatus: 500},
+		{name: "quota exceeded", err: &currencyapi.APIError{StatusCode: 429, Code: "quota_exceeded", RetryAfter: 90 * time.Second}, wantType: TypeQuotaExceeded, wantStatus: 503, retryAfter: 90, upstreamCode: "quota_exceeded"},
+		{name: "quota reserved", err: &currencyapi.QuotaReservedError{Remaining: 10, Reserve: 100}, wantType: TypeQuotaExceeded, wantStatus: 503},
+		{name: "rate limited", err: &currencyapi.HTTPError{StatusCode: 429, RetryAfter: 1500 * time.Millisecond}, wantType: TypeRateLimited, wantStatus: 429, retryAfter: 2, upstreamCode: "429"},
+		{name: "provider rate limited", err: fmt.Errorf("latest: %w", &currencyapi.APIError{StatusCode: 429, Code: "rate_limit_exceeded", RetryAfter: 30 * time.Second}), wantType: TypeRateLimited, wantStatus: 429, retryAfter: 30, upstreamCode: "rate_limit_exceeded"},
+		{name: "provider rate limited without code", err: &currencyapi.APIError{StatusCode: 429}, wantType: TypeRateLimited, wantStatus: 429, upstreamCode: "429"},
+		{name: "upstream down", err: fmt.Errorf("latest: %w", &currencyapi.HTTPError{StatusCode: 502}), wantType: TypeUpstreamUnavailable, wantStatus: 503, upstreamCode: "502"},
+		{name: "network", err: &currencyapi.RequestError{Op: "execute_request", Err: errors.New("connection refused")}, wantType: TypeUpstreamUnavailable, wantStatus: 503},
+		{name: "rates not found", err: &worker.NotFoundError{Currency: "XYZ"}, wantType: TypeNotFound, wantStatus: 404},
+		{name: "history query", err: &worker.QueryError{Field: "quote", Message: "required"}, wantType: TypeInvalidRequest, wantStatus: 400},
+		{name: "invalid currency", err: fmt.Errorf("%w: US", worker.ErrInvalidCurrency), wantType: TypeInvalidRequest, wantStatus: 400},
+		{name: "already tracked", err: worker.ErrAlreadyTracked, wantType: TypeConflict, wantStatus: 409},
+		{name: "rule not found", err: alert.ErrRuleNotFound, wantType: TypeNotFound, wantStatus: 404},
+		{name: "duplicate rule", err: fmt.Errorf("%w: gbp-moves", alert.ErrRuleExists), wantType: TypeConflict, wantStatus: 409},
+		{name: "invalid rule", err: &alert.ValidationError{Field: "kind", Message: "must be crosses or change"}, wantType: TypeInvalidRequest, wantStatus: 400},
+		{name: "unknown", err: errors.New("disk full"), wantType: TypeInternal, wantStatus: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FromError(tt.err)
+			if p.Type != tt.wantType || p.Status != tt.wantStatus || p.Title != titles[tt.wantType] {
+				t.Errorf("FromError() = %+v, want %s %d", p, tt.wantType, tt.wantStatus)
+			}
+			if p.RetryAfter != tt.retryAfter || p.UpstreamCode != tt.upstreamCode {
+				t.Errorf("FromError() extensions = %d %q, want %d %q", p.RetryAfter, p.UpstreamCode, tt.retryAfter, tt.upstreamCode)
+			}
+		})
+	}
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.New())
+	router.GET("/", func(c *gin.Context) {
+		Abort(c, &currencyapi.HTTPError{StatusCode: 429, RetryAfter: 30 * time.Second})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "req-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %s", got, ContentType)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	want := map[string]any{
+		"type":          TypeRateLimited,
+		"title":         "Rate limited",
+		"status":        float64(429),
+		"detail":        "the currency data provider is rate limiting requests",
+		"instance":      "urn:request-id:req-42",
+		"retry_after":   float64(30),
+		"upstream_code": "429",
+	}
+	if fmt.Sprint(body) != fmt.Sprint(want) {
+		t.Errorf("Body = %v, want %v", body, want)
+	}
+}
@@ -0,0 +1,60 @@
+// Package requestid tags every request with an ID, echoed in the X-Request-ID header,
+// so that responses and logs can be correlated.
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header carries the request ID in requests and responses
+const Header = "X-Request-ID"
+
+// maxLength is the length of the longest request ID accepted from clients
+const maxLength = 128
+
+// contextKey is the gin context key of the request ID
+const contextKey = "request_id"
+
+// New returns a middleware that keeps the request ID sent by the client, or generates
+// one if it's missing or malformed, and sets it on the response
+func New() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = generate()
+		}
+		c.Set(contextKey, id)
+		c.Header(Header, id)
+		c.Next()
+	}
+}
+
+// Get returns the ID of the request, or an empty string without the middleware
+func Get(c *gin.Context) string {
+	return c.GetString(contextKey)
+}
+
+// valid reports whether a client request ID can be used as is
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// generate returns a random request ID
+func generate() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
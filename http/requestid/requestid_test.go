@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(New())
+	router.GET("/", func(c *gin.Context) {
+		c.String(200, Get(c))
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		wantKept bool
+	}{
+		{name: "missing", header: ""},
+		{name: "client id", header: "7f1c2e-abc_1.2", wantKept: true},
+		{name: "malformed", header: "id with spaces"},
+		{name: "too long", header: strings.Repeat("a", maxLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(Header)
+			if id == "" || w.Body.String() != id {
+				t.Fatalf("Response ID %q, request ID %q", id, w.Body)
+			}
+			if kept := id == tt.header; kept != tt.wantKept {
+				t.Errorf("Request ID = %q for header %q", id, tt.header)
+			}
+		})
+	}
+}
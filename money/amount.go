@@ -0,0 +1,330 @@
+// Package money provides exact fixed-point decimal amounts and currency-tagged
+// money values for accounting-grade currency calculations.
+package money
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxExponent limits the exponent accepted by Parse to keep amounts reasonably sized
+const maxExponent = 1000
+
+// ErrDivisionByZero is returned when dividing by a zero amount
+var ErrDivisionByZero = errors.New("money: division by zero")
+
+// ParseError is returned when a string is not a valid decimal number
+type ParseError struct {
+	Value string
+}
+
+func (e *ParseError) Error() string {
+	return "money: invalid decimal " + strconv.Quote(e.Value)
+}
+
+// RoundingMode selects how amounts are rounded to fewer decimal places
+type RoundingMode int
+
+// Supported rounding modes
+const (
+	// HalfEven rounds ties to the nearest even digit (banker's rounding)
+	HalfEven RoundingMode = iota
+	// HalfUp rounds ties away from zero
+	HalfUp
+)
+
+// Amount is an exact decimal number: an arbitrary precision integer coefficient
+// scaled by a number of decimal places. Amounts are immutable and the zero value is 0.
+type Amount struct {
+	coef  *big.Int // nil means zero
+	scale int32    // number of decimal places
+}
+
+// New returns value×10^-scale, e.g. New(1250, 2) is 12.50
+func New(value int64, scale int32) Amount {
+	return newAmount(big.NewInt(value), scale)
+}
+
+// newAmount creates an amount, folding a negative scale into the coefficient
+func newAmount(coef *big.Int, scale int32) Amount {
+	if scale < 0 {
+		return Amount{coef: new(big.Int).Mul(coef, pow10(-scale))}
+	}
+	return Amount{coef: coef, scale: scale}
+}
+
+// Parse parses a decimal number such as "-12.50" or "1.5e-7"
+func Parse(s string) (Amount, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exponent, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || exponent > maxExponent || exponent < -maxExponent {
+			return Amount{}, &ParseError{Value: s}
+		}
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	digits := whole + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Amount{}, &ParseError{Value: s}
+	}
+
+	coef, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Amount{}, &ParseError{Value: s}
+	}
+	return newAmount(coef, int32(int64(len(fraction))-exponent)), nil
+}
+
+// MustParse is like Parse but panics if the string is not a valid decimal number
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewFromFloat returns the shortest decimal that converts back to f, e.g. 0.1 is exactly 0.1.
+// It panics if f is NaN or infinite.
+func NewFromFloat(f float64) Amount {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic("money: cannot convert " + strconv.FormatFloat(f, 'g', -1, 64) + " to an amount")
+	}
+	return MustParse(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// int returns the coefficient; callers must not modify it
+func (a Amount) int() *big.Int {
+	if a.coef == nil {
+		return new(big.Int)
+	}
+	return a.coef
+}
+
+// pow10 returns 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the coefficient of a at a larger or equal scale
+func (a Amount) rescale(scale int32) *big.Int {
+	if scale == a.scale {
+		return a.int()
+	}
+	return new(big.Int).Mul(a.int(), pow10(scale-a.scale))
+}
+
+// align returns the coefficients of a and b at their common scale
+func align(a, b Amount) (*big.Int, *big.Int, int32) {
+	scale := max(a.scale, b.scale)
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// Scale returns the number of decimal places
+func (a Amount) Scale() int32 {
+	return a.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of a
+func (a Amount) Sign() int {
+	return a.int().Sign()
+}
+
+// IsZero reports whether a is zero
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Cmp compares a and b and returns -1, 0 or +1
+func (a Amount) Cmp(b Amount) int {
+	x, y, _ := align(a, b)
+	return x.Cmp(y)
+}
+
+// Equal reports whether a and b are numerically equal, regardless of their scale
+func (a Amount) Equal(b Amount) bool {
+	return a.Cmp(b) == 0
+}
+
+// Neg returns -a
+func (a Amount) Neg() Amount {
+	return Amount{coef: new(big.Int).Neg(a.int()), scale: a.scale}
+}
+
+// Abs returns |a|
+func (a Amount) Abs() Amount {
+	return Amount{coef: new(big.Int).Abs(a.int()), scale: a.scale}
+}
+
+// Add returns a+b
+func (a Amount) Add(b Amount) Amount {
+	x, y, scale := align(a, b)
+	return Amount{coef: new(big.Int).Add(x, y), scale: scale}
+}
+
+// Sub returns a-b
+func (a Amount) Sub(b Amount) Amount {
+	x, y, scale := align(a, b)
+	return Amount{coef: new(big.Int).Sub(x, y), scale: scale}
+}
+
+// Mul returns the exact product a×b
+func (a Amount) Mul(b Amount) Amount {
+	return Amount{coef: new(big.Int).Mul(a.int(), b.int()), scale: a.scale + b.scale}
+}
+
+// Div returns a/b rounded to the given number of decimal places
+func (a Amount) Div(b Amount, places int32, mode RoundingMode) (Amount, error) {
+	if b.IsZero() {
+		return Amount{}, ErrDivisionByZero
+	}
+	places = max(places, 0)
+
+	// a/b = (ca×10^-sa)/(cb×10^-sb), so the result coefficient is ca×10^(places+sb-sa)/cb
+	n, d := a.int(), b.int()
+	if exp := places + b.scale - a.scale; exp >= 0 {
+		n = new(big.Int).Mul(n, pow10(exp))
+	} else {
+		d = new(big.Int).Mul(d, pow10(-exp))
+	}
+	return Amount{coef: quoRound(n, d, mode), scale: places}, nil
+}
+
+// Round returns a with exactly the given number of decimal places, rounding if needed
+func (a Amount) Round(places int32, mode RoundingMode) Amount {
+	places = max(places, 0)
+	if places >= a.scale {
+		return Amount{coef: a.rescale(places), scale: places}
+	}
+	return Amount{coef: quoRound(a.int(), pow10(a.scale-places), mode), scale: places}
+}
+
+// RoundForCurrency rounds a to the minor unit of a currency. Rounding is the rounding
+// increment in minor units as reported by CurrencyInfo, e.g. 5 rounds CHF to 0.05;
+// 0 and 1 round to the minor unit itself.
+func (a Amount) RoundForCurrency(decimalDigits, rounding int, mode RoundingMode) Amount {
+	places := int32(max(decimalDigits, 0))
+	if rounding <= 1 {
+		return a.Round(places, mode)
+	}
+
+	// Count increments of rounding×10^-places: ca×10^places / (rounding×10^sa)
+	increment := big.NewInt(int64(rounding))
+	n := new(big.Int).Mul(a.int(), pow10(places))
+	d := new(big.Int).Mul(increment, pow10(a.scale))
+	count := quoRound(n, d, mode)
+	return Amount{coef: count.Mul(count, increment), scale: places}
+}
+
+// Normalize returns a without trailing zeros after the decimal point
+func (a Amount) Normalize() Amount {
+	coef, scale := a.int(), a.scale
+	if coef.Sign() == 0 {
+		return Amount{}
+	}
+
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	for scale > 0 {
+		q.QuoRem(coef, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		coef, scale = new(big.Int).Set(q), scale-1
+	}
+	return Amount{coef: coef, scale: scale}
+}
+
+// quoRound returns n/d rounded to an integer with the rounding mode
+func quoRound(n, d *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// Compare the remainder with half of the divisor
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(new(big.Int).Abs(d))
+	if half > 0 || (half == 0 && (mode == HalfUp || q.Bit(0) == 1)) {
+		if n.Sign()*d.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Float64 returns the nearest float64 value of a
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// String formats a as a plain decimal number keeping its scale, e.g. "12.50"
+func (a Amount) String() string {
+	coef := a.int()
+	digits := new(big.Int).Abs(coef).String()
+
+	sign := ""
+	if coef.Sign() < 0 {
+		sign = "-"
+	}
+	if a.scale == 0 {
+		return sign + digits
+	}
+
+	if pad := int(a.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(a.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON encodes a as a JSON number without loss of precision
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding a decimal number
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return &ParseError{Value: string(data)}
+		}
+		data = []byte(s)
+	}
+	return a.UnmarshalText(data)
+}
+
+// MarshalText encodes a as a decimal number
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes a decimal number, e.g. from an XML attribute
+func (a *Amount) UnmarshalText(text []byte) error {
+	parsed, err := Parse(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
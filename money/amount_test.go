@@ -0,0 +1,241 @@
+package money
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "0", want: "0"},
+		{input: "12.50", want: "12.50"},
+		{input: "-0.001", want: "-0.001"},
+		{input: "+3", want: "3"},
+		{input: ".5", want: "0.5"},
+		{input: "1.5e-7", want: "0.00000015"},
+		{input: "2.5E3", want: "2500"},
+		{input: "123456789012345678901234567890.123456789", want: "123456789012345678901234567890.123456789"},
+		{input: "", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "12a", wantErr: true},
+		{input: "1e", wantErr: true},
+		{input: "1e99999", wantErr: true},
+		{input: "NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Errorf("Parse(%q) error = %v, want ParseError", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromFloat(t *testing.T) {
+	if got := NewFromFloat(0.1); got.String() != "0.1" {
+		t.Errorf("NewFromFloat(0.1) = %s, want 0.1", got)
+	}
+	if got := NewFromFloat(41.5); got.String() != "41.5" {
+		t.Errorf("NewFromFloat(41.5) = %s, want 41.5", got)
+	}
+	if got := New(1250, 2); got.String() != "12.50" {
+		t.Errorf("New(1250, 2) = %s, want 12.50", got)
+	}
+	if got := New(5, -2); got.String() != "500" {
+		t.Errorf("New(5, -2) = %s, want 500", got)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("0.1")
+	b := MustParse("0.2")
+
+	if got := a.Add(b); got.String() != "0.3" || !got.Equal(MustParse("0.30")) {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", got)
+	}
+	if got := a.Sub(b); got.String() != "-0.1" || got.Sign() != -1 {
+		t.Errorf("0.1 - 0.2 = %s, want -0.1", got)
+	}
+	if got := MustParse("19.99").Mul(MustParse("3")); got.String() != "59.97" {
+		t.Errorf("19.99 × 3 = %s, want 59.97", got)
+	}
+	if got := MustParse("1.25").Mul(MustParse("0.0000001")); got.String() != "0.000000125" {
+		t.Errorf("1.25 × 0.0000001 = %s, want 0.000000125", got)
+	}
+	if got := MustParse("-2.50").Abs(); got.String() != "2.50" {
+		t.Errorf("|-2.50| = %s, want 2.50", got)
+	}
+	if got := MustParse("2.50").Neg(); got.String() != "-2.50" {
+		t.Errorf("-(2.50) = %s, want -2.50", got)
+	}
+	if MustParse("1.0").Cmp(MustParse("0.99")) != 1 || MustParse("1").Cmp(MustParse("1.000")) != 0 {
+		t.Error("Unexpected comparison result")
+	}
+
+	var zero Amount
+	if !zero.IsZero() || zero.String() != "0" || !zero.Add(a).Equal(a) {
+		t.Errorf("Zero value must behave as 0, got %s", zero)
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		places int32
+		mode   RoundingMode
+		want   string
+	}{
+		{a: "1", b: "3", places: 4, mode: HalfEven, want: "0.3333"},
+		{a: "2", b: "3", places: 4, mode: HalfEven, want: "0.6667"},
+		{a: "-2", b: "3", places: 2, mode: HalfUp, want: "-0.67"},
+		{a: "1", b: "8", places: 2, mode: HalfEven, want: "0.12"},
+		{a: "1", b: "8", places: 2, mode: HalfUp, want: "0.13"},
+		{a: "100", b: "0.8", places: 0, mode: HalfEven, want: "125"},
+		{a: "0.000001", b: "1000", places: 12, mode: HalfEven, want: "0.000000001000"},
+	}
+
+	for _, tt := range tests {
+		got, err := MustParse(tt.a).Div(MustParse(tt.b), tt.places, tt.mode)
+		if err != nil {
+			t.Fatalf("%s / %s error = %v", tt.a, tt.b, err)
+		}
+		if got.String() != tt.want {
+			t.Errorf("%s / %s = %s, want %s", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := MustParse("1").Div(Amount{}, 2, HalfEven); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Expected ErrDivisionByZero, got %v", err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		input  string
+		places int32
+		mode   RoundingMode
+		want   string
+	}{
+		{input: "2.345", places: 2, mode: HalfEven, want: "2.34"},
+		{input: "2.355", places: 2, mode: HalfEven, want: "2.36"},
+		{input: "2.345", places: 2, mode: HalfUp, want: "2.35"},
+		{input: "-2.345", places: 2, mode: HalfUp, want: "-2.35"},
+		{input: "-2.345", places: 2, mode: HalfEven, want: "-2.34"},
+		{input: "2.3451", places: 2, mode: HalfEven, want: "2.35"},
+		{input: "0.5", places: 0, mode: HalfEven, want: "0"},
+		{input: "1.5", places: 0, mode: HalfEven, want: "2"},
+		{input: "1.5", places: 2, mode: HalfEven, want: "1.50"},
+	}
+
+	for _, tt := range tests {
+		if got := MustParse(tt.input).Round(tt.places, tt.mode); got.String() != tt.want {
+			t.Errorf("Round(%s, %d, %v) = %s, want %s", tt.input, tt.places, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestRoundForCurrency(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		decimalDigits int
+		rounding      int
+		mode          RoundingMode
+		want          string
+	}{
+		{name: "USD", input: "10.125", decimalDigits: 2, mode: HalfEven, want: "10.12"},
+		{name: "USD half up", input: "10.125", decimalDigits: 2, mode: HalfUp, want: "10.13"},
+		{name: "JPY", input: "1234.5", decimalDigits: 0, mode: HalfEven, want: "1234"},
+		{name: "KWD", input: "1.23456", decimalDigits: 3, mode: HalfEven, want: "1.235"},
+		{name: "CHF cash", input: "10.024", decimalDigits: 2, rounding: 5, mode: HalfEven, want: "10.00"},
+		{name: "CHF cash up", input: "10.03", decimalDigits: 2, rounding: 5, mode: HalfEven, want: "10.05"},
+		{name: "CHF cash tie", input: "10.075", decimalDigits: 2, rounding: 5, mode: HalfEven, want: "10.10"},
+		{name: "negative increment", input: "-10.03", decimalDigits: 2, rounding: 5, mode: HalfUp, want: "-10.05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParse(tt.input).RoundForCurrency(tt.decimalDigits, tt.rounding, tt.mode)
+			if got.String() != tt.want {
+				t.Errorf("RoundForCurrency(%s) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"1.2500": "1.25",
+		"100":    "100",
+		"0.000":  "0",
+		"-3.10":  "-3.1",
+	}
+	for input, want := range tests {
+		if got := MustParse(input).Normalize(); got.String() != want {
+			t.Errorf("Normalize(%s) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var decoded struct {
+		Number Amount  `json:"number"`
+		String Amount  `json:"string"`
+		Null   Amount  `json:"null"`
+		Tiny   *Amount `json:"tiny"`
+	}
+	input := `{"number": 0.123456789012345678, "string": "41.50", "null": null, "tiny": 2.34e-05}`
+	if err := json.Unmarshal([]byte(input), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.Number.String() != "0.123456789012345678" {
+		t.Errorf("Number = %s, want full precision", decoded.Number)
+	}
+	if decoded.String.String() != "41.50" || !decoded.Null.IsZero() || decoded.Tiny.String() != "0.0000234" {
+		t.Errorf("Unexpected decoded amounts: %+v", decoded)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"number":0.123456789012345678,"string":41.50,"null":0,"tiny":0.0000234}`
+	if string(encoded) != want {
+		t.Errorf("Marshal() = %s, want %s", encoded, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"number": true}`), &decoded); err == nil {
+		t.Error("Expected error for non-numeric JSON")
+	}
+}
+
+func TestAmountXML(t *testing.T) {
+	var decoded struct {
+		Rate Amount `xml:"rate,attr"`
+	}
+	if err := xml.Unmarshal([]byte(`<Cube rate="1.1645"/>`), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.Rate.String() != "1.1645" {
+		t.Errorf("Rate = %s, want 1.1645", decoded.Rate)
+	}
+}
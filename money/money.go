@@ -0,0 +1,64 @@
+package money
+
+// CurrencyMismatchError is returned when combining money in different currencies
+type CurrencyMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return "money: currency mismatch: expected " + e.Expected + ", got " + e.Actual
+}
+
+// Money is an amount in a currency
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney creates money from an amount and a currency code
+func NewMoney(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// String formats money as the amount followed by the currency code, e.g. "12.50 USD"
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Add returns m+o; both must be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, &CurrencyMismatchError{Expected: m.Currency, Actual: o.Currency}
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m-o; both must be in the same currency
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, &CurrencyMismatchError{Expected: m.Currency, Actual: o.Currency}
+	}
+	return Money{Amount: m.Amount.Sub(o.Amount), Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by a factor
+func (m Money) Mul(factor Amount) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+// Convert returns the exact value of m in another currency, given the value
+// of one unit of m's currency in that currency
+func (m Money) Convert(rate Amount, currency string) Money {
+	return Money{Amount: m.Amount.Mul(rate), Currency: currency}
+}
+
+// Round rounds m to the minor unit of its currency, see Amount.RoundForCurrency
+func (m Money) Round(decimalDigits, rounding int, mode RoundingMode) Money {
+	return Money{Amount: m.Amount.RoundForCurrency(decimalDigits, rounding, mode), Currency: m.Currency}
+}
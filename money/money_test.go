@@ -0,0 +1,69 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	price := NewMoney(MustParse("19.99"), "USD")
+	shipping := NewMoney(MustParse("5.01"), "USD")
+
+	total, err := price.Add(shipping)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if total.String() != "25.00 USD" {
+		t.Errorf("Total = %s, want 25.00 USD", total)
+	}
+
+	change, err := total.Sub(price.Mul(MustParse("2")))
+	if err != nil {
+		t.Fatalf("Sub() error = %v", err)
+	}
+	if change.String() != "-14.98 USD" {
+		t.Errorf("Change = %s, want -14.98 USD", change)
+	}
+
+	_, err = price.Add(NewMoney(MustParse("1"), "EUR"))
+	var mismatch *CurrencyMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Expected != "USD" || mismatch.Actual != "EUR" {
+		t.Errorf("Expected CurrencyMismatchError, got %v", err)
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	amount := NewMoney(MustParse("1234.56"), "USD")
+
+	converted := amount.Convert(MustParse("0.858737"), "EUR")
+	if converted.String() != "1060.16235072 EUR" {
+		t.Errorf("Converted = %s, want exact product", converted)
+	}
+	if rounded := converted.Round(2, 0, HalfEven); rounded.String() != "1060.16 EUR" {
+		t.Errorf("Rounded = %s, want 1060.16 EUR", rounded)
+	}
+
+	yen := amount.Convert(MustParse("155.1"), "JPY").Round(0, 0, HalfEven)
+	if yen.String() != "191480 JPY" {
+		t.Errorf("Converted JPY = %s, want 191480 JPY", yen)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	encoded, err := json.Marshal(NewMoney(MustParse("0.10"), "EUR"))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(encoded) != `{"amount":0.10,"currency":"EUR"}` {
+		t.Errorf("Marshal() = %s", encoded)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.Currency != "EUR" || decoded.Amount.String() != "0.10" {
+		t.Errorf("Unmarshal() = %+v", decoded)
+	}
+}
@@ -2,13 +2,24 @@ package web
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/BohdanKyryliuk/golang/alert"
+	"github.com/BohdanKyryliuk/golang/config"
 	"github.com/BohdanKyryliuk/golang/currency_converter"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/http/auth"
 	"github.com/BohdanKyryliuk/golang/http/handler"
+	"github.com/BohdanKyryliuk/golang/http/problem"
+	"github.com/BohdanKyryliuk/golang/http/requestid"
 	"github.com/BohdanKyryliuk/golang/worker"
 	"github.com/gin-gonic/gin"
 )
@@ -19,11 +30,16 @@ type ServerConfig struct {
 	CurrencyClient *currency_converter.Client
 	// WorkerConfig is the configuration for currency rate workers
 	WorkerConfig *worker.Config
+	// AlertConfig enables threshold alerts on the rates of the workers (optional)
+	AlertConfig *config.AlertConfig
+	// AuthConfig requires API keys on the API routes (optional; without it they are public)
+	AuthConfig *config.AuthConfig
 }
 
+// StartServer starts the server with configuration loaded from environment variables
 func StartServer() {
 	// Initialize currency converter client from environment variables
-	currencyClient, err := currency_converter.NewFromEnv()
+	currencyClient, err := newCurrencyClient()
 	if err != nil {
 		log.Printf("Warning: Currency converter not available: %v", err)
 		// Continue without currency endpoints
@@ -33,90 +49,237 @@ func StartServer() {
 	var workerConfig *worker.Config
 	if currencyClient != nil {
 		cfg := worker.DefaultConfig()
+		if dir := config.RatesStoreDir(); dir != "" {
+			store, err := worker.OpenFileStore(dir)
+			if err != nil {
+				log.Printf("Warning: Rates are kept in memory only: %v", err)
+			} else {
+				cfg.Store = store
+				cfg.HistoryFile = filepath.Join(dir, "history.json")
+			}
+		}
 		workerConfig = &cfg
 	}
 
+	alertConfig, err := config.LoadAlertConfig()
+	if err != nil {
+		log.Printf("Warning: Alerts disabled: %v", err)
+	}
+
+	// Starting without the configured keys would expose the API, so invalid keys are fatal
+	authConfig, err := config.LoadAuthConfig()
+	if err != nil {
+		log.Fatalf("Invalid API keys: %v", err)
+	}
+
 	StartServerWithConfig(ServerConfig{
 		CurrencyClient: currencyClient,
 		WorkerConfig:   workerConfig,
+		AlertConfig:    alertConfig,
+		AuthConfig:     authConfig,
 	})
 }
 
+// newCurrencyClient creates the currency converter client, backed by an in-memory
+// fake in offline mode so that the server runs without an API key or network
+func newCurrencyClient() (*currency_converter.Client, error) {
+	if config.OfflineMode() {
+		log.Println("Offline mode: serving currency data from an in-memory fake")
+		fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+		return currency_converter.NewWithAPIClient(currency_converter.Config{}, fake)
+	}
+	return currency_converter.NewFromEnv()
+}
+
 // StartServerWithConfig starts the server with the provided configuration
 func StartServerWithConfig(cfg ServerConfig) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	s, err := newServer(ctx, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Request contexts derive from ctx, so that long-lived streams end on shutdown
+	server := &http.Server{
+		Addr:        ":3001",
+		Handler:     s.router,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	// Handle graceful shutdown
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		<-sigCh
+
+		log.Println("Shutting down server...")
+
+		// Stop workers first
+		if s.manager != nil {
+			stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := s.manager.Stop(stopCtx); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			stopCancel()
+		}
+		if cfg.WorkerConfig != nil && cfg.WorkerConfig.Store != nil {
+			if err := cfg.WorkerConfig.Store.Close(); err != nil {
+				log.Printf("Warning: Failed to close rate store: %v", err)
+			}
+		}
+
+		// Cancelling ends the streams and the alert deliveries, which are dead-lettered
+		cancel()
+		if s.alertsDone != nil {
+			<-s.alertsDone
+		}
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		shutdownCancel()
+	}()
+
+	log.Println("Listening on :3001")
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// server is the router of the web server and the background services behind it
+type server struct {
+	router     *gin.Engine
+	manager    *worker.Manager // Nil without workers
+	alertsDone <-chan struct{} // Closed once the alerts have stopped; nil without alerts
+}
+
+// newServer creates the router for cfg and starts the workers and alerts it serves,
+// which run until ctx is done
+func newServer(ctx context.Context, cfg ServerConfig) (*server, error) {
 	// Create Gin router
 	router := gin.Default()
+	router.Use(requestid.New())
+	router.NoRoute(func(c *gin.Context) {
+		problem.Abort(c, problem.NotFound("no route for "+c.Request.Method+" "+c.Request.URL.Path))
+	})
 
 	// Register basic routes
 	router.GET("/", handler.Hello)
 	router.GET("/count", handler.Counter)
 	router.POST("/count", handler.Counter)
+	router.GET("/openapi.json", handler.ServeOpenAPI)
+	router.GET("/docs", handler.Docs)
 
-	var workerManager *worker.Manager
+	s := &server{router: router}
+
+	// Data routes need the rates:read scope and management routes the admin scope,
+	// unless no API keys are configured
+	readGroup := router.Group("")
+	adminGroup := router.Group("")
+	if cfg.AuthConfig != nil {
+		authenticator, err := auth.New(cfg.AuthConfig)
+		if err != nil {
+			return nil, err
+		}
+		readGroup.Use(authenticator.Require(config.ScopeRatesRead))
+		adminGroup.Use(authenticator.Require(config.ScopeAdmin))
+		router.GET("/me/usage", handler.NewAccount(authenticator).Usage)
+		log.Printf("API key authentication enabled with %d keys", len(cfg.AuthConfig.Keys))
+	}
 
 	// Only register currency handlers if the client is available
 	if cfg.CurrencyClient != nil {
 		currencyHandler := handler.NewCurrency(cfg.CurrencyClient)
 
 		// Create currency route group
-		currencyGroup := router.Group("/currency")
+		currencyGroup := readGroup.Group("/currency")
 		{
 			currencyGroup.GET("/status", currencyHandler.Status)
 			currencyGroup.GET("/currencies", currencyHandler.Currencies)
 			currencyGroup.GET("/latest", currencyHandler.LatestRates)
+			currencyGroup.GET("/historical", currencyHandler.HistoricalRates)
+			currencyGroup.GET("/convert", currencyHandler.Convert)
 		}
 
 		// Initialize and start workers if config is provided
 		if cfg.WorkerConfig != nil {
-			var err error
-			workerManager, err = worker.NewManager(cfg.CurrencyClient.APIClient(), *cfg.WorkerConfig)
+			manager, err := worker.NewManager(cfg.CurrencyClient.APIClient(), *cfg.WorkerConfig)
 			if err != nil {
 				log.Printf("Warning: Failed to create worker manager: %v", err)
 			} else {
-				if err := workerManager.Start(ctx); err != nil {
+				s.manager = manager
+				if err := manager.Start(ctx); err != nil {
 					log.Printf("Warning: Failed to start workers: %v", err)
 				} else {
 					// Register rate handlers
-					ratesHandler := handler.NewRates(workerManager)
+					ratesHandler := handler.NewRates(manager)
 
 					// Create rates route group
-					ratesGroup := router.Group("/rates")
+					ratesGroup := readGroup.Group("/rates")
 					{
 						ratesGroup.GET("", ratesHandler.GetRate)
 						ratesGroup.GET("/all", ratesHandler.GetAllRates)
 						ratesGroup.GET("/status", ratesHandler.GetWorkerStatus)
+						ratesGroup.GET("/history", ratesHandler.GetHistory)
+						ratesGroup.GET("/stream", ratesHandler.Stream)
+					}
+					readGroup.GET("/ws", ratesHandler.WebSocket)
+
+					workersGroup := adminGroup.Group("/rates/workers")
+					{
+						workersGroup.POST("/:currency", ratesHandler.AddWorker)
+						workersGroup.DELETE("/:currency", ratesHandler.RemoveWorker)
+					}
+
+					if cfg.AlertConfig != nil {
+						if rules, err := loadAlertRules(cfg.AlertConfig); err != nil {
+							log.Printf("Warning: Alerts disabled: %v", err)
+						} else {
+							s.alertsDone = startAlerts(ctx, manager, rules, cfg.AlertConfig)
+
+							alertsHandler := handler.NewAlerts(rules)
+							alertsGroup := adminGroup.Group("/alerts")
+							{
+								alertsGroup.GET("", alertsHandler.List)
+								alertsGroup.POST("", alertsHandler.Create)
+								alertsGroup.GET("/:id", alertsHandler.Get)
+								alertsGroup.PUT("/:id", alertsHandler.Update)
+								alertsGroup.DELETE("/:id", alertsHandler.Delete)
+							}
+						}
 					}
 				}
 			}
 		}
 	}
+	return s, nil
+}
 
-	// Create HTTP server
-	server := &gin.Engine{}
-	*server = *router
-
-	// Handle graceful shutdown
-	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-		<-sigCh
+// loadAlertRules loads the alert rules from the rules file, or starts with none
+func loadAlertRules(cfg *config.AlertConfig) (*alert.Rules, error) {
+	if cfg.RulesFile == "" {
+		return alert.NewRules(), nil
+	}
+	return alert.LoadRules(cfg.RulesFile)
+}
 
-		log.Println("Shutting down server...")
+// startAlerts evaluates the alert rules against the rates stored by the workers until
+// ctx is done; the returned channel is closed once the pending deliveries have ended
+func startAlerts(ctx context.Context, manager *worker.Manager, rules *alert.Rules, cfg *config.AlertConfig) <-chan struct{} {
+	webhook := alert.NewWebhook(cfg.WebhookURL, cfg.WebhookSecret, alert.WithDeadLetterFile(cfg.DeadLetterFile))
+	engine := alert.NewEngine(rules, webhook, alert.WithTrackedBases(manager.GetCurrencies))
+	sub := manager.Subscribe(worker.EventFilter{}, worker.WithBuffer(256))
 
-		// Stop workers first
-		if workerManager != nil {
-			workerManager.Stop()
-		}
-
-		cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer sub.Close()
+		engine.Run(ctx, sub.Events())
 	}()
-
-	log.Println("Listening on :3001")
-
-	if err := router.Run(":3001"); err != nil {
-		log.Fatal(err)
-	}
+	log.Printf("Alerts enabled with %d rules", len(rules.List()))
+	return done
 }
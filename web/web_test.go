@@ -0,0 +1,164 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/config"
+	"github.com/BohdanKyryliuk/golang/currency_converter"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/http/handler"
+	"github.com/BohdanKyryliuk/golang/http/openapi"
+	"github.com/BohdanKyryliuk/golang/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// pages are the routes that aren't part of the documented API
+var pages = map[string]bool{
+	"GET /":             true,
+	"GET /count":        true,
+	"POST /count":       true,
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+}
+
+const (
+	readerKey = "reader-0123456789abcdef"
+	adminKey  = "admin-0123456789abcdef"
+)
+
+// newTestServer creates a server with every feature enabled, backed by a fake API client
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	client, err := currency_converter.NewWithAPIClient(currency_converter.Config{}, fake)
+	if err != nil {
+		t.Fatalf("NewWithAPIClient() error = %v", err)
+	}
+	workerConfig := worker.DefaultConfig()
+	workerConfig.FetchInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := newServer(ctx, ServerConfig{
+		CurrencyClient: client,
+		WorkerConfig:   &workerConfig,
+		AlertConfig: &config.AlertConfig{
+			WebhookURL:    "http://127.0.0.1:0/alerts",
+			WebhookSecret: "secret",
+			RulesFile:     filepath.Join(t.TempDir(), "rules.json"),
+		},
+		AuthConfig: &config.AuthConfig{Keys: []config.APIKey{
+			{Name: "reader", Key: readerKey, Scopes: []string{config.ScopeRatesRead}},
+			{Name: "admin", Key: adminKey, Scopes: []string{config.ScopeAdmin}},
+		}},
+	})
+	if err != nil {
+		cancel()
+		t.Fatalf("newServer() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if s.manager != nil {
+			s.manager.Stop(context.Background())
+		}
+		cancel()
+		if s.alertsDone != nil {
+			<-s.alertsDone
+		}
+	})
+	return s
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	s := newTestServer(t)
+	spec := handler.OpenAPI()
+
+	registered := make(map[string]bool)
+	for _, route := range s.router.Routes() {
+		key := route.Method + " " + route.Path
+		if pages[key] {
+			continue
+		}
+		registered[route.Method+" "+openapi.Path(route.Path)] = true
+		if _, ok := spec.Operation(route.Method, route.Path); !ok {
+			t.Errorf("Route %s is missing from the OpenAPI document", key)
+		}
+	}
+
+	// Every feature is enabled, so every documented operation is served
+	for path, item := range spec.Paths {
+		for method := range *item {
+			if key := strings.ToUpper(method) + " " + path; !registered[key] {
+				t.Errorf("Documented operation %s is not registered", key)
+			}
+		}
+	}
+}
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	s := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") || doc.Paths["/rates/workers/{currency}"]["post"] == nil {
+		t.Errorf("Unexpected OpenAPI document: %s", w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `fetch("/openapi.json")`) {
+		t.Errorf("Unexpected docs page %d: %.200s", w.Code, w.Body)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		key    string
+		want   int
+	}{
+		{name: "public page", method: http.MethodGet, target: "/openapi.json", want: http.StatusOK},
+		{name: "data without key", method: http.MethodGet, target: "/currency/latest", want: http.StatusUnauthorized},
+		{name: "data", method: http.MethodGet, target: "/currency/latest", key: readerKey, want: http.StatusOK},
+		{name: "stream without key", method: http.MethodGet, target: "/ws", want: http.StatusUnauthorized},
+		{name: "management as reader", method: http.MethodDelete, target: "/rates/workers/USD", key: readerKey, want: http.StatusForbidden},
+		{name: "alerts as reader", method: http.MethodGet, target: "/alerts", key: readerKey, want: http.StatusForbidden},
+		{name: "alerts as admin", method: http.MethodGet, target: "/alerts", key: adminKey, want: http.StatusOK},
+		{name: "usage", method: http.MethodGet, target: "/me/usage", key: readerKey, want: http.StatusOK},
+		{name: "usage without key", method: http.MethodGet, target: "/me/usage", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.key)
+			}
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body)
+			}
+		})
+	}
+}
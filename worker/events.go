@@ -0,0 +1,265 @@
+package worker
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// DefaultEventBuffer is the number of events buffered for a subscriber by default
+const DefaultEventBuffer = 64
+
+// DefaultEventRetention is the number of recent events kept to resume subscriptions
+const DefaultEventRetention = 256
+
+// changePercentPlaces is the number of decimal places of a percent change
+const changePercentPlaces = 4
+
+// RateChange is the change of the rate of one quote currency
+type RateChange struct {
+	// Old is the previously stored rate; zero if the quote currency is new
+	Old money.Amount `json:"old,omitzero"`
+	New money.Amount `json:"new"`
+	// ChangePercent is the change from Old to New in percent; zero if the quote currency is new
+	ChangePercent money.Amount `json:"change_percent"`
+}
+
+// RateUpdated is published every time a worker stores fresh rates of a base currency
+type RateUpdated struct {
+	// ID increases with every event published by a manager
+	ID        uint64                `json:"id"`
+	Base      string                `json:"base"`
+	Changes   map[string]RateChange `json:"changes"`
+	Provider  string                `json:"provider,omitempty"`
+	Derived   bool                  `json:"derived,omitempty"`
+	FetchedAt time.Time             `json:"fetched_at"`
+}
+
+// EventFilter selects the events and quote currencies delivered to a subscriber
+type EventFilter struct {
+	// Base selects a base currency; empty selects all of them
+	Base string
+	// Quotes selects quote currencies; empty selects all of them. Events without
+	// any of the quote currencies aren't delivered.
+	Quotes []string
+}
+
+// apply returns the event restricted to the filter, or false if it doesn't match
+func (f EventFilter) apply(event RateUpdated) (RateUpdated, bool) {
+	if f.Base != "" && f.Base != event.Base {
+		return event, false
+	}
+	if len(f.Quotes) == 0 {
+		return event, true
+	}
+
+	changes := make(map[string]RateChange, len(f.Quotes))
+	for _, quote := range f.Quotes {
+		if change, ok := event.Changes[quote]; ok {
+			changes[quote] = change
+		}
+	}
+	event.Changes = changes
+	return event, len(changes) > 0
+}
+
+// DropPolicy decides which event is dropped when a subscriber's buffer is full
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest buffered event to make room, so that a slow
+	// subscriber always catches up to the latest rates (default)
+	DropOldest DropPolicy = iota
+	// DropNewest discards the event being published and keeps the buffered ones
+	DropNewest
+)
+
+// SubscribeOption is a functional option for configuring a Subscription
+type SubscribeOption func(*Subscription)
+
+// WithBuffer sets the number of events buffered for the subscriber
+func WithBuffer(size int) SubscribeOption {
+	return func(s *Subscription) {
+		if size > 0 {
+			s.buffer = size
+		}
+	}
+}
+
+// WithDropPolicy sets which event is dropped when the buffer is full
+func WithDropPolicy(policy DropPolicy) SubscribeOption {
+	return func(s *Subscription) {
+		s.policy = policy
+	}
+}
+
+// WithLastEventID resumes after the event with the given ID: the recent events
+// published after it are delivered first
+func WithLastEventID(id uint64) SubscribeOption {
+	return func(s *Subscription) {
+		s.resumeAfter = &id
+	}
+}
+
+// Subscription delivers the rate events matching a filter until it is closed.
+// Publishing never waits for a subscriber: when its buffer is full, an event is
+// dropped according to the drop policy and counted.
+type Subscription struct {
+	filter  EventFilter
+	buffer  int
+	policy  DropPolicy
+	events  chan RateUpdated
+	dropped atomic.Uint64
+	hub     *eventHub
+
+	resumeAfter *uint64 // ID of the last event the subscriber has seen, if resuming
+}
+
+// Events returns the channel of events, closed when the subscription is closed
+func (s *Subscription) Events() <-chan RateUpdated {
+	return s.events
+}
+
+// Dropped returns the number of events dropped because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the delivery of events and closes the events channel
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// deliver buffers an event without blocking; hub.mu must be held. When the buffer
+// is full, DropNewest discards the event and DropOldest discards the oldest buffered
+// event to make room for it; either way one event is counted in Dropped.
+func (s *Subscription) deliver(event RateUpdated) {
+	select {
+	case s.events <- event:
+		return
+	default:
+	}
+
+	if s.policy == DropOldest {
+		select {
+		case <-s.events:
+		default:
+		}
+		select {
+		case s.events <- event:
+		default:
+			// Unreachable: hub.mu keeps other publishers out and the subscriber only
+			// makes more room
+		}
+	}
+	s.dropped.Add(1)
+}
+
+// eventHub publishes rate events to subscriptions and keeps the recent events
+type eventHub struct {
+	mu            sync.Mutex
+	lastID        uint64
+	recent        ring[RateUpdated]
+	subscriptions map[*Subscription]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscriptions: make(map[*Subscription]struct{})}
+}
+
+func (h *eventHub) subscribe(filter EventFilter, opts ...SubscribeOption) *Subscription {
+	filter.Base = strings.ToUpper(filter.Base)
+	filter.Quotes = slices.Clone(filter.Quotes)
+	for i, quote := range filter.Quotes {
+		filter.Quotes[i] = strings.ToUpper(quote)
+	}
+
+	s := &Subscription{filter: filter, buffer: DefaultEventBuffer, hub: h}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.events = make(chan RateUpdated, s.buffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Replay while holding the lock, so that no event is missed or delivered twice
+	if s.resumeAfter != nil {
+		for _, event := range h.recent.all() {
+			if event.ID <= *s.resumeAfter {
+				continue
+			}
+			if filtered, ok := s.filter.apply(event); ok {
+				s.deliver(filtered)
+			}
+		}
+	}
+	h.subscriptions[s] = struct{}{}
+	return s
+}
+
+func (h *eventHub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscriptions[s]; ok {
+		delete(h.subscriptions, s)
+		close(s.events)
+	}
+}
+
+// publish assigns the next ID to the event and delivers it to the matching subscriptions
+func (h *eventHub) publish(event RateUpdated) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.ID = h.lastID
+	h.recent.add(event, DefaultEventRetention)
+	for s := range h.subscriptions {
+		if filtered, ok := s.filter.apply(event); ok {
+			s.deliver(filtered)
+		}
+	}
+}
+
+// newRateUpdated returns the event of storing data over previous, which may be nil
+func newRateUpdated(previous, data *RateData) RateUpdated {
+	event := RateUpdated{
+		Base:      data.BaseCurrency,
+		Changes:   make(map[string]RateChange, len(data.Rates)),
+		Provider:  data.Provider,
+		Derived:   data.Derived,
+		FetchedAt: data.FetchedAt,
+	}
+	for quote, rate := range data.Rates {
+		change := RateChange{New: rate.Value}
+		if previous != nil {
+			if old, ok := previous.Rates[quote]; ok {
+				change.Old = old.Value
+				change.ChangePercent = PercentChange(old.Value, rate.Value)
+			}
+		}
+		event.Changes[quote] = change
+	}
+	return event
+}
+
+// PercentChange returns the change from old to new in percent, or zero if old is zero
+func PercentChange(old, new money.Amount) money.Amount {
+	ratio, err := new.Sub(old).Mul(money.New(100, 0)).Div(old, changePercentPlaces, money.HalfEven)
+	if err != nil {
+		return money.Amount{}
+	}
+	return ratio.Normalize()
+}
+
+// Subscribe returns a subscription to the rate events matching the filter.
+// The subscription survives restarts of the manager until it is closed.
+func (m *Manager) Subscribe(filter EventFilter, opts ...SubscribeOption) *Subscription {
+	return m.events.subscribe(filter, opts...)
+}
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// receive returns the next event of a subscription
+func receive(t *testing.T, sub *Subscription) RateUpdated {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatal("Subscription closed")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+	return RateUpdated{}
+}
+
+func TestManagerSubscribe(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC))
+	fake := currencyapitest.NewFakeClient("USD", map[string]float64{"EUR": 0.8, "GBP": 0.75})
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Minute,
+		Backoff:       &BackoffPolicy{},
+		Clock:         clock,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	all := manager.Subscribe(EventFilter{})
+	defer all.Close()
+	eur := manager.Subscribe(EventFilter{Base: "usd", Quotes: []string{"eur"}})
+	defer eur.Close()
+	other := manager.Subscribe(EventFilter{Base: "GBP"})
+	defer other.Close()
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Stop(context.Background()) })
+
+	first := receive(t, all)
+	if first.ID != 1 || first.Base != "USD" || len(first.Changes) != 3 {
+		t.Fatalf("Unexpected first event: %+v", first)
+	}
+	if change := first.Changes["EUR"]; !change.Old.IsZero() || change.New.String() != "0.8" || !change.ChangePercent.IsZero() {
+		t.Errorf("Expected a new EUR rate without a change, got %+v", change)
+	}
+
+	fake.SetRate("EUR", 0.82)
+	clock.waitForWaiter(t)
+	clock.Advance(time.Minute)
+
+	second := receive(t, all)
+	change := second.Changes["EUR"]
+	if second.ID != 2 || change.Old.String() != "0.8" || change.New.String() != "0.82" || change.ChangePercent.String() != "2.5" {
+		t.Errorf("Unexpected EUR change: %+v", second)
+	}
+	if change := second.Changes["GBP"]; !change.ChangePercent.IsZero() || change.Old.IsZero() {
+		t.Errorf("Expected an unchanged GBP rate, got %+v", change)
+	}
+
+	for _, want := range []uint64{1, 2} {
+		event := receive(t, eur)
+		if _, ok := event.Changes["EUR"]; event.ID != want || len(event.Changes) != 1 || !ok {
+			t.Errorf("Expected event %d with only EUR, got %+v", want, event)
+		}
+	}
+	select {
+	case event := <-other.Events():
+		t.Errorf("Expected no GBP events, got %+v", event)
+	default:
+	}
+}
+
+func TestSubscriptionDropPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy DropPolicy
+		want   []uint64
+	}{
+		{name: "drop oldest", policy: DropOldest, want: []uint64{4, 5}},
+		{name: "drop newest", policy: DropNewest, want: []uint64{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := newEventHub()
+			sub := hub.subscribe(EventFilter{}, WithBuffer(2), WithDropPolicy(tt.policy))
+
+			data := testRates("USD", "0.8")
+			for range 5 {
+				hub.publish(newRateUpdated(nil, data))
+			}
+			if sub.Dropped() != 3 {
+				t.Errorf("Dropped() = %d, want 3", sub.Dropped())
+			}
+
+			sub.Close()
+			var got []uint64
+			for event := range sub.Events() {
+				got = append(got, event.ID)
+			}
+			if len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+				t.Errorf("Delivered events %v, want %v", got, tt.want)
+			}
+
+			// Publishing to a closed subscription is a no-op
+			hub.publish(newRateUpdated(nil, data))
+			sub.Close()
+		})
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		old, new, want string
+	}{
+		{old: "1.10", new: "1.111", want: "1"},
+		{old: "40", new: "39.5", want: "-1.25"},
+		{old: "3", new: "4", want: "33.3333"},
+		{old: "0", new: "1", want: "0"},
+	}
+	for _, tt := range tests {
+		got := PercentChange(money.MustParse(tt.old), money.MustParse(tt.new))
+		if got.String() != tt.want {
+			t.Errorf("PercentChange(%s, %s) = %s, want %s", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionResume(t *testing.T) {
+	hub := newEventHub()
+	for _, base := range []string{"USD", "GBP", "USD"} {
+		hub.publish(newRateUpdated(nil, testRates(base, "0.8")))
+	}
+
+	resumed := hub.subscribe(EventFilter{Base: "USD"}, WithLastEventID(1))
+	defer resumed.Close()
+	live := hub.subscribe(EventFilter{Base: "USD"})
+	defer live.Close()
+
+	hub.publish(newRateUpdated(nil, testRates("USD", "0.81")))
+
+	for _, want := range []uint64{3, 4} {
+		if event := receive(t, resumed); event.ID != want {
+			t.Errorf("Resumed subscription got event %d, want %d", event.ID, want)
+		}
+	}
+	if event := receive(t, live); event.ID != 4 {
+		t.Errorf("Live subscription got event %d, want 4", event.ID)
+	}
+}
@@ -0,0 +1,321 @@
+package worker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Files of a FileStore directory
+const (
+	snapshotFileName = "snapshot.json"
+	logFileName      = "rates.log"
+)
+
+// DefaultCompactEvery is the number of logged updates after which a FileStore writes a new snapshot
+const DefaultCompactEvery = 100
+
+// FileStore is a rate store persisted to a directory as a snapshot plus an append-only
+// log of the updates made since. The log is compacted into a new snapshot after every
+// CompactEvery updates. Rates loaded from disk are marked as stale until they are replaced.
+//
+// Readers only wait for in-memory updates: mu guards the rates, while logMu serializes
+// the slow log writes and compactions and is always taken before mu.
+type FileStore struct {
+	dir          string
+	compactEvery int
+	data         map[string]*RateData
+	mu           sync.RWMutex
+
+	log        *os.File
+	logSize    int64 // Size of the valid part of the log
+	logEntries int   // Number of updates in the log
+	logMu      sync.Mutex
+}
+
+// FileStoreOption is a functional option for configuring a FileStore
+type FileStoreOption func(*FileStore)
+
+// WithCompactEvery sets the number of logged updates after which the log is compacted
+func WithCompactEvery(updates int) FileStoreOption {
+	return func(s *FileStore) {
+		if updates > 0 {
+			s.compactEvery = updates
+		}
+	}
+}
+
+// logEntry is one line of the append-only log; an entry without data is a deletion
+type logEntry struct {
+	Currency string    `json:"currency"`
+	Data     *RateData `json:"data,omitempty"`
+}
+
+// StoreError is returned when a FileStore fails to read or write its files
+type StoreError struct {
+	Op  string
+	Err error
+}
+
+func (e *StoreError) Error() string {
+	return "rate store " + e.Op + ": " + e.Err.Error()
+}
+
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
+
+// OpenFileStore opens the file-backed rate store in dir, creating the directory if needed,
+// and loads the rates persisted by a previous run
+func OpenFileStore(dir string, opts ...FileStoreOption) (*FileStore, error) {
+	s := &FileStore{
+		dir:          dir,
+		compactEvery: DefaultCompactEvery,
+		data:         make(map[string]*RateData),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, &StoreError{Op: "open", Err: err}
+	}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, &StoreError{Op: "load snapshot", Err: err}
+	}
+	if err := s.replayLog(); err != nil {
+		return nil, &StoreError{Op: "replay log", Err: err}
+	}
+
+	for _, data := range s.data {
+		data.Stale = true
+	}
+	return s, nil
+}
+
+// loadSnapshot loads the last snapshot, if any
+func (s *FileStore) loadSnapshot() error {
+	content, err := os.ReadFile(filepath.Join(s.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, &s.data)
+}
+
+// replayLog applies the logged updates on top of the snapshot and opens the log for appending.
+// A torn last line left by a crash during a write is cut off.
+func (s *FileStore) replayLog() error {
+	file, err := os.OpenFile(filepath.Join(s.dir, logFileName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				log.Printf("Rate store: dropping incomplete log entry in %s", s.dir)
+			}
+			break
+		}
+		if err != nil {
+			file.Close()
+			return err
+		}
+
+		var entry logEntry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Currency == "" {
+			log.Printf("Rate store: dropping corrupt log entries in %s from offset %d", s.dir, s.logSize)
+			break
+		}
+		if entry.Data == nil {
+			delete(s.data, entry.Currency)
+		} else {
+			s.data[entry.Currency] = entry.Data
+		}
+		s.logSize += int64(len(line))
+		s.logEntries++
+	}
+
+	if err := file.Truncate(s.logSize); err != nil {
+		file.Close()
+		return err
+	}
+	s.log = file
+	return nil
+}
+
+// Set stores rate data for a currency and appends it to the log. The data is served
+// even if persisting it fails.
+func (s *FileStore) Set(currency string, data *RateData) error {
+	if data == nil {
+		return &StoreError{Op: "set", Err: errors.New("rate data is required")}
+	}
+	return s.update("set", logEntry{Currency: currency, Data: data})
+}
+
+// Delete removes the rate data of a currency and appends the deletion to the log
+func (s *FileStore) Delete(currency string) error {
+	return s.update("delete", logEntry{Currency: currency})
+}
+
+// update applies a log entry in memory and appends it to the log. Holding logMu
+// across both keeps the log in the order the updates were applied in.
+func (s *FileStore) update(op string, entry logEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return &StoreError{Op: op, Err: err}
+	}
+	line = append(line, '\n')
+
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	s.mu.Lock()
+	if entry.Data == nil {
+		delete(s.data, entry.Currency)
+	} else {
+		s.data[entry.Currency] = entry.Data
+	}
+	s.mu.Unlock()
+
+	if s.log == nil {
+		return &StoreError{Op: op, Err: os.ErrClosed}
+	}
+
+	if err := s.append(line); err != nil {
+		return &StoreError{Op: op, Err: err}
+	}
+	if s.logEntries >= s.compactEvery {
+		if err := s.compact(); err != nil {
+			return &StoreError{Op: "compact", Err: err}
+		}
+	}
+	return nil
+}
+
+// append durably writes a line to the log, cutting off a partial write on failure
+func (s *FileStore) append(line []byte) error {
+	if _, err := s.log.Write(line); err != nil {
+		_ = s.log.Truncate(s.logSize)
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+	s.logSize += int64(len(line))
+	s.logEntries++
+	return nil
+}
+
+// compact writes all rates to a new snapshot and empties the log. The snapshot is
+// replaced atomically, so a crash at any point leaves either the old snapshot with the
+// full log or the new snapshot with a log whose replay doesn't change it.
+func (s *FileStore) compact() error {
+	s.mu.RLock()
+	content, err := json.Marshal(s.data)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := filepath.Join(s.dir, snapshotFileName+".tmp")
+	if err := writeFileSync(tmpPath, content); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filepath.Join(s.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	s.logSize = 0
+	s.logEntries = 0
+	return nil
+}
+
+// Get retrieves rate data for a currency
+func (s *FileStore) Get(currency string) (*RateData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.data[currency]
+	if !ok {
+		return nil, &NotFoundError{Currency: currency}
+	}
+	return data, nil
+}
+
+// GetAll returns all stored rate data
+func (s *FileStore) GetAll() map[string]*RateData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]*RateData, len(s.data))
+	for k, v := range s.data {
+		result[k] = v
+	}
+	return result
+}
+
+// Close compacts the log so that the next start loads a single snapshot and closes the store
+func (s *FileStore) Close() error {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	if s.log == nil {
+		return nil
+	}
+
+	var err error
+	if s.logEntries > 0 {
+		if compactErr := s.compact(); compactErr != nil {
+			err = &StoreError{Op: "compact", Err: compactErr}
+		}
+	}
+	if closeErr := s.log.Close(); closeErr != nil && err == nil {
+		err = &StoreError{Op: "close", Err: closeErr}
+	}
+	s.log = nil
+	return err
+}
+
+// writeFileSync writes a file and flushes it to disk
+func writeFileSync(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncDir flushes a directory so that a rename in it survives a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,287 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// testRates returns rate data with a single EUR rate
+func testRates(base, eur string) *RateData {
+	return &RateData{
+		BaseCurrency:  base,
+		Rates:         map[string]currencyapi.RateInfo{"EUR": {Code: "EUR", Value: money.MustParse(eur)}},
+		LastUpdatedAt: "2025-12-05T23:59:59Z",
+		FetchedAt:     time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func openTestStore(t *testing.T, dir string, opts ...FileStoreOption) *FileStore {
+	t.Helper()
+	store, err := OpenFileStore(dir, opts...)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	return store
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir)
+	if err := store.Set("USD", testRates("USD", "0.91")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("USD", testRates("USD", "0.92")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("GBP", testRates("GBP", "1.17")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := store.Get("USD"); got.Stale {
+		t.Error("Fresh rates must not be stale")
+	}
+
+	// Reopen without closing, as after a crash
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+
+	if all := reopened.GetAll(); len(all) != 2 {
+		t.Fatalf("Expected 2 currencies, got %d", len(all))
+	}
+	got, err := reopened.Get("USD")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !got.Stale || got.Rates["EUR"].Value.String() != "0.92" || !got.FetchedAt.Equal(testRates("USD", "0").FetchedAt) {
+		t.Errorf("Unexpected reloaded rates: %+v", got)
+	}
+
+	// Replacing the rates clears the stale flag
+	if err := reopened.Set("USD", testRates("USD", "0.93")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := reopened.Get("USD"); got.Stale {
+		t.Error("Refreshed rates must not be stale")
+	}
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir, WithCompactEvery(3))
+	for _, value := range []string{"0.90", "0.91", "0.92", "0.93"} {
+		if err := store.Set("USD", testRates("USD", value)); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); err != nil {
+		t.Fatalf("Expected a snapshot after compaction: %v", err)
+	}
+	if store.logEntries != 1 {
+		t.Errorf("Expected 1 log entry after compaction, got %d", store.logEntries)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, logFileName)); err != nil || info.Size() != 0 {
+		t.Errorf("Expected an empty log after Close(), got %v, %v", info, err)
+	}
+	if err := store.Set("USD", testRates("USD", "0.94")); err == nil {
+		t.Error("Set() on a closed store should fail")
+	}
+
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+	if got, err := reopened.Get("USD"); err != nil || got.Rates["EUR"].Value.String() != "0.93" {
+		t.Errorf("Get() = %+v, %v", got, err)
+	}
+}
+
+func TestFileStoreCrashRecovery(t *testing.T) {
+	tests := []struct {
+		name string
+		// corrupt damages the store directory as a crash could
+		corrupt func(t *testing.T, dir string)
+	}{
+		{
+			name: "torn log write",
+			corrupt: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, logFileName), `{"currency":"USD","data":{"base_cur`)
+			},
+		},
+		{
+			name: "leftover temporary snapshot",
+			corrupt: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, snapshotFileName+".tmp"), `{"USD":`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := openTestStore(t, dir)
+			if err := store.Set("USD", testRates("USD", "0.92")); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			tt.corrupt(t, dir)
+
+			reopened := openTestStore(t, dir)
+			defer reopened.Close()
+			if got, err := reopened.Get("USD"); err != nil || got.Rates["EUR"].Value.String() != "0.92" {
+				t.Fatalf("Get() = %+v, %v", got, err)
+			}
+
+			// New updates are appended after the last complete entry
+			if err := reopened.Set("GBP", testRates("GBP", "1.17")); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			again := openTestStore(t, dir)
+			defer again.Close()
+			if all := again.GetAll(); len(all) != 2 {
+				t.Errorf("Expected 2 currencies, got %d", len(all))
+			}
+		})
+	}
+}
+
+func TestFileStoreDelete(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir)
+	if err := store.Set("USD", testRates("USD", "0.92")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("GBP", testRates("GBP", "1.17")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Delete("USD"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+	if _, err := reopened.Get("USD"); err == nil {
+		t.Error("Deleted rates should stay deleted after a restart")
+	}
+	if _, err := reopened.Get("GBP"); err != nil {
+		t.Errorf("Get(GBP) error = %v", err)
+	}
+}
+
+func TestFileStoreConcurrentUpdates(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir, WithCompactEvery(5))
+
+	// Readers don't wait for log writes and compactions
+	store.logMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		store.Get("USD")
+		store.GetAll()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Get() waited for the log")
+	}
+	store.logMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, currency := range []string{"USD", "GBP", "JPY", "CHF"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20 {
+				if err := store.Set(currency, testRates(currency, fmt.Sprintf("0.%02d", i))); err != nil {
+					t.Errorf("Set() error = %v", err)
+				}
+				store.GetAll()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The log and snapshots end up with the rates served from memory
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+	for currency, want := range store.GetAll() {
+		got, err := reopened.Get(currency)
+		if err != nil || got.Rates["EUR"].Value.String() != want.Rates["EUR"].Value.String() {
+			t.Errorf("Get(%s) = %+v, %v, want %+v", currency, got, err, want)
+		}
+	}
+}
+
+func TestManagerWarmStart(t *testing.T) {
+	dir := t.TempDir()
+	previous := openTestStore(t, dir)
+	if err := previous.Set("USD", testRates("USD", "0.5")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := previous.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	store := openTestStore(t, dir)
+	defer store.Close()
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+		Store:         store,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	// The last known rates are served before the first fetch
+	rates, err := manager.GetRates("USD")
+	if err != nil {
+		t.Fatalf("GetRates() error = %v", err)
+	}
+	if !rates.Stale || rates.Rates["EUR"].Value.String() != "0.5" {
+		t.Errorf("Expected stale rates of the previous run, got %+v", rates)
+	}
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if rates, _ := manager.GetRates("USD"); !rates.Stale {
+			if got := rates.Rates["EUR"].Value.String(); got != "0.92" {
+				t.Errorf("Expected fresh EUR rate 0.92, got %s", got)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for fresh rates")
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
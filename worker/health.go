@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+)
+
+// FailingAfter is the number of consecutive failed fetches after which a worker is failing
+const FailingAfter = 3
+
+// HealthStatus is the verdict on the health of a worker or of all workers
+type HealthStatus string
+
+// Health verdicts
+const (
+	HealthHealthy  HealthStatus = "healthy"  // Every fetch succeeds
+	HealthDegraded HealthStatus = "degraded" // Recent fetches failed, or only some workers are failing
+	HealthFailing  HealthStatus = "failing"  // No worker is able to fetch rates
+)
+
+// ErrorKind classifies a fetch error
+type ErrorKind string
+
+// Fetch error kinds, from the currencyapi error types
+const (
+	ErrorQuotaExceeded ErrorKind = "quota_exceeded"
+	ErrorQuotaReserved ErrorKind = "quota_reserved"
+	ErrorValidation    ErrorKind = "validation"
+	ErrorAPI           ErrorKind = "api"
+	ErrorHTTP          ErrorKind = "http"
+	ErrorParse         ErrorKind = "parse"
+	ErrorRequest       ErrorKind = "request"
+	ErrorUnknown       ErrorKind = "unknown"
+)
+
+// classifyError returns the kind of a fetch error
+func classifyError(err error) ErrorKind {
+	switch {
+	case currencyapi.IsQuotaExceededError(err):
+		return ErrorQuotaExceeded
+	case currencyapi.IsQuotaReservedError(err):
+		return ErrorQuotaReserved
+	case currencyapi.IsValidationError(err):
+		return ErrorValidation
+	case currencyapi.IsAPIError(err):
+		return ErrorAPI
+	case currencyapi.IsHTTPError(err):
+		return ErrorHTTP
+	case currencyapi.IsParseError(err):
+		return ErrorParse
+	case currencyapi.IsRequestError(err):
+		return ErrorRequest
+	}
+	return ErrorUnknown
+}
+
+// WorkerHealth describes the recent fetches of a worker
+type WorkerHealth struct {
+	Status              HealthStatus `json:"status"`
+	LastSuccess         time.Time    `json:"last_success,omitzero"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastErrorKind       ErrorKind    `json:"last_error_kind,omitempty"`
+	LastErrorTemporary  bool         `json:"last_error_temporary,omitempty"`
+	LastErrorAt         time.Time    `json:"last_error_at,omitzero"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastLatencyMs       int64        `json:"last_latency_ms"`
+	NextRun             time.Time    `json:"next_run,omitzero"`
+	// DerivedFrom is the pivot currency whose worker fetches the rates of a derived currency
+	DerivedFrom string `json:"derived_from,omitempty"`
+}
+
+// WorkerStatus describes the lifecycle and the health of all workers
+type WorkerStatus struct {
+	Running    bool                    `json:"running"`
+	State      State                   `json:"state"`
+	Status     HealthStatus            `json:"status"`
+	Currencies []string                `json:"currencies"`
+	Workers    map[string]WorkerHealth `json:"workers"`
+}
+
+// healthTracker records the fetches of a worker
+type healthTracker struct {
+	health WorkerHealth
+	mu     sync.Mutex
+}
+
+func (t *healthTracker) recordSuccess(at time.Time, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.health.LastSuccess = at
+	t.health.LastLatencyMs = latency.Milliseconds()
+	t.health.ConsecutiveFailures = 0
+}
+
+func (t *healthTracker) recordFailure(at time.Time, latency time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.health.LastError = err.Error()
+	t.health.LastErrorKind = classifyError(err)
+	t.health.LastErrorTemporary = currencyapi.IsTemporaryError(err)
+	t.health.LastErrorAt = at
+	t.health.LastLatencyMs = latency.Milliseconds()
+	t.health.ConsecutiveFailures++
+}
+
+func (t *healthTracker) scheduleNext(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.health.NextRun = at
+}
+
+// snapshot returns the recorded health with its verdict
+func (t *healthTracker) snapshot() WorkerHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := t.health
+	switch {
+	case health.ConsecutiveFailures == 0:
+		health.Status = HealthHealthy
+	case health.ConsecutiveFailures >= FailingAfter || health.LastSuccess.IsZero():
+		health.Status = HealthFailing
+	default:
+		health.Status = HealthDegraded
+	}
+	return health
+}
+
+// overallHealth returns healthy if all workers are, failing if all of them are, or degraded
+func overallHealth(workers []WorkerHealth) HealthStatus {
+	if len(workers) == 0 {
+		return HealthFailing
+	}
+
+	healthy, failing := 0, 0
+	for _, health := range workers {
+		switch health.Status {
+		case HealthHealthy:
+			healthy++
+		case HealthFailing:
+			failing++
+		}
+	}
+	switch {
+	case healthy == len(workers):
+		return HealthHealthy
+	case failing == len(workers):
+		return HealthFailing
+	}
+	return HealthDegraded
+}
+
+// GetWorkerStatus returns the lifecycle state, the health of every tracked currency and an
+// overall verdict. A stopped manager is failing since it doesn't refresh any rates.
+func (m *Manager) GetWorkerStatus() WorkerStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := WorkerStatus{
+		Running:    m.state == StateRunning,
+		State:      m.state,
+		Currencies: slices.Clone(m.config.Currencies),
+		Workers:    make(map[string]WorkerHealth, len(m.workers)),
+	}
+
+	healths := make([]WorkerHealth, 0, len(m.workers))
+	for currency, rw := range m.workers {
+		health := rw.worker.health.snapshot()
+		status.Workers[currency] = health
+		healths = append(healths, health)
+	}
+	// Derived currencies share the health of the pivot worker
+	if pivot, ok := status.Workers[m.config.Pivot]; ok {
+		for _, currency := range m.config.Currencies {
+			if currency != m.config.Pivot {
+				derived := pivot
+				derived.DerivedFrom = m.config.Pivot
+				status.Workers[currency] = derived
+			}
+		}
+	}
+
+	if status.Running {
+		status.Status = overallHealth(healths)
+	} else {
+		status.Status = HealthFailing
+	}
+	return status
+}
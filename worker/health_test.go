@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{name: "quota exceeded", err: &currencyapi.APIError{StatusCode: 429, Code: "quota_exceeded"}, want: ErrorQuotaExceeded},
+		{name: "quota reserved", err: &currencyapi.QuotaReservedError{}, want: ErrorQuotaReserved},
+		{name: "validation", err: &currencyapi.ValidationError{Field: "base_currency"}, want: ErrorValidation},
+		{name: "api", err: &currencyapi.APIError{StatusCode: 401, Code: "invalid_api_key"}, want: ErrorAPI},
+		{name: "http", err: &currencyapi.HTTPError{StatusCode: 503}, want: ErrorHTTP},
+		{name: "parse", err: &currencyapi.ParseError{Endpoint: "latest", Err: errors.New("eof")}, want: ErrorParse},
+		{name: "request", err: &currencyapi.RequestError{Op: "latest", Err: context.DeadlineExceeded}, want: ErrorRequest},
+		{name: "unknown", err: errors.New("boom"), want: ErrorUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.want {
+				t.Errorf("classifyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkerHealthVerdict(t *testing.T) {
+	var tracker healthTracker
+	now := time.Now()
+	failure := &currencyapi.HTTPError{StatusCode: 503}
+
+	tracker.recordFailure(now, time.Millisecond, failure)
+	if got := tracker.snapshot().Status; got != HealthFailing {
+		t.Errorf("A worker that never succeeded should be failing, got %v", got)
+	}
+
+	tracker.recordSuccess(now, 120*time.Millisecond)
+	health := tracker.snapshot()
+	if health.Status != HealthHealthy || health.LastLatencyMs != 120 || health.LastErrorKind != ErrorHTTP {
+		t.Errorf("Unexpected health after success: %+v", health)
+	}
+
+	for i := 1; i <= FailingAfter; i++ {
+		tracker.recordFailure(now, time.Millisecond, failure)
+		want := HealthDegraded
+		if i == FailingAfter {
+			want = HealthFailing
+		}
+		if health := tracker.snapshot(); health.Status != want || health.ConsecutiveFailures != i {
+			t.Errorf("After %d failures: got %v with %d failures, want %v", i, health.Status, health.ConsecutiveFailures, want)
+		}
+	}
+	if !tracker.snapshot().LastErrorTemporary {
+		t.Error("A 503 should be reported as temporary")
+	}
+
+	tests := []struct {
+		name     string
+		statuses []HealthStatus
+		want     HealthStatus
+	}{
+		{name: "no workers", want: HealthFailing},
+		{name: "all healthy", statuses: []HealthStatus{HealthHealthy, HealthHealthy}, want: HealthHealthy},
+		{name: "one degraded", statuses: []HealthStatus{HealthHealthy, HealthDegraded}, want: HealthDegraded},
+		{name: "one failing", statuses: []HealthStatus{HealthHealthy, HealthFailing}, want: HealthDegraded},
+		{name: "all failing", statuses: []HealthStatus{HealthFailing, HealthFailing}, want: HealthFailing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healths := make([]WorkerHealth, len(tt.statuses))
+			for i, status := range tt.statuses {
+				healths[i].Status = status
+			}
+			if got := overallHealth(healths); got != tt.want {
+				t.Errorf("overallHealth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagerWorkerStatus(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"EUR"},
+		FetchInterval: time.Hour,
+		Pivot:         "USD",
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if status := manager.GetWorkerStatus(); status.Status != HealthFailing || status.State != StateIdle {
+		t.Errorf("A manager that isn't running should be failing, got %+v", status)
+	}
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+	waitForRates(t, manager, "USD", "EUR")
+
+	status := manager.GetWorkerStatus()
+	if status.Status != HealthHealthy || !status.Running || len(status.Workers) != 2 {
+		t.Fatalf("Unexpected status: %+v", status)
+	}
+	pivot := status.Workers["USD"]
+	if pivot.LastSuccess.IsZero() || !pivot.NextRun.After(pivot.LastSuccess) {
+		t.Errorf("Unexpected pivot health: %+v", pivot)
+	}
+	if derived := status.Workers["EUR"]; derived.DerivedFrom != "USD" || !derived.LastSuccess.Equal(pivot.LastSuccess) {
+		t.Errorf("Unexpected derived health: %+v", derived)
+	}
+
+	// Failures are reported until the pivot worker is failing
+	fake.SetError(currencyapitest.EndpointLatest, &currencyapi.HTTPError{StatusCode: 503})
+	if err := manager.SetInterval(5 * time.Millisecond); err != nil {
+		t.Fatalf("SetInterval() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for manager.GetWorkerStatus().Status != HealthFailing {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for failing status: %+v", manager.GetWorkerStatus())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if health := manager.GetWorkerStatus().Workers["USD"]; health.LastErrorKind != ErrorHTTP || health.ConsecutiveFailures < FailingAfter {
+		t.Errorf("Unexpected failing health: %+v", health)
+	}
+}
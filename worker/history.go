@@ -0,0 +1,272 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+// DefaultHistorySize is the number of samples kept per base currency, a day of one minute fetches
+const DefaultHistorySize = 1440
+
+// averagePlaces is the number of decimal places kept by averaged rates
+const averagePlaces = 18
+
+// Aggregation selects how the samples of a history bucket are combined
+type Aggregation string
+
+// Supported aggregations
+const (
+	AggregateLast Aggregation = "last" // Last sample of the bucket
+	AggregateAvg  Aggregation = "avg"  // Mean of the samples of the bucket
+	AggregateOHLC Aggregation = "ohlc" // Open, high, low and close of the bucket
+)
+
+// HistorySample is the rates of a base currency observed at one point in time
+type HistorySample struct {
+	Time  time.Time               `json:"time"`
+	Rates map[string]money.Amount `json:"rates"`
+}
+
+// HistoryQuery selects the rate history of a currency pair
+type HistoryQuery struct {
+	Base        string
+	Quote       string
+	From        time.Time     // Inclusive lower bound (optional)
+	To          time.Time     // Inclusive upper bound (optional)
+	Interval    time.Duration // Bucket size; zero returns every sample
+	Aggregation Aggregation   // How buckets are combined (default: last)
+}
+
+// HistoryPoint is a sample or a bucket of the rate history of a currency pair
+type HistoryPoint struct {
+	Time    time.Time    `json:"time"` // Sample time or bucket start
+	Value   money.Amount `json:"value"`
+	Open    money.Amount `json:"open,omitzero"`
+	High    money.Amount `json:"high,omitzero"`
+	Low     money.Amount `json:"low,omitzero"`
+	Close   money.Amount `json:"close,omitzero"`
+	Samples int          `json:"samples"`
+}
+
+// QueryError is returned for an invalid history query
+type QueryError struct {
+	Field   string
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return "invalid history query: " + e.Field + ": " + e.Message
+}
+
+// History keeps a bounded ring buffer of rate samples per base currency
+type History struct {
+	size   int
+	series map[string]*ring[HistorySample]
+	mu     sync.RWMutex
+}
+
+// ring is a fixed size buffer of items in the order they were added
+type ring[T any] struct {
+	items []T
+	next  int // Index the next item is written to once the buffer is full
+}
+
+func (r *ring[T]) add(item T, size int) {
+	if len(r.items) < size {
+		r.items = append(r.items, item)
+		return
+	}
+	r.items[r.next] = item
+	r.next = (r.next + 1) % size
+}
+
+// all returns the items from the oldest to the newest
+func (r *ring[T]) all() []T {
+	items := make([]T, 0, len(r.items))
+	items = append(items, r.items[r.next:]...)
+	return append(items, r.items[:r.next]...)
+}
+
+// NewHistory creates a history keeping up to size samples per base currency
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{
+		size:   size,
+		series: make(map[string]*ring[HistorySample]),
+	}
+}
+
+// LoadHistory creates a history from a file written by Save; a missing file gives an empty history
+func LoadHistory(path string, size int) (*History, error) {
+	h := NewHistory(size)
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, &StoreError{Op: "load history", Err: err}
+	}
+
+	var series map[string][]HistorySample
+	if err := json.Unmarshal(content, &series); err != nil {
+		return nil, &StoreError{Op: "load history", Err: err}
+	}
+	for base, samples := range series {
+		for _, sample := range samples {
+			h.add(base, sample)
+		}
+	}
+	return h, nil
+}
+
+// Save writes the history to a file, replacing it atomically
+func (h *History) Save(path string) error {
+	h.mu.RLock()
+	series := make(map[string][]HistorySample, len(h.series))
+	for base, r := range h.series {
+		series[base] = r.all()
+	}
+	h.mu.RUnlock()
+
+	content, err := json.Marshal(series)
+	if err != nil {
+		return &StoreError{Op: "save history", Err: err}
+	}
+
+	tmpPath := path + ".tmp"
+	if err := writeFileSync(tmpPath, content); err != nil {
+		return &StoreError{Op: "save history", Err: err}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return &StoreError{Op: "save history", Err: err}
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		return &StoreError{Op: "save history", Err: err}
+	}
+	return nil
+}
+
+// Record adds the rates of a fetch to the history of their base currency
+func (h *History) Record(data *RateData) {
+	sample := HistorySample{
+		Time:  data.FetchedAt,
+		Rates: make(map[string]money.Amount, len(data.Rates)),
+	}
+	for code, rate := range data.Rates {
+		sample.Rates[code] = rate.Value
+	}
+	h.add(data.BaseCurrency, sample)
+}
+
+func (h *History) add(base string, sample HistorySample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.series[base]
+	if !ok {
+		r = &ring[HistorySample]{}
+		h.series[base] = r
+	}
+	r.add(sample, h.size)
+}
+
+// Query returns the rate history of a currency pair, oldest first
+func (h *History) Query(q HistoryQuery) ([]HistoryPoint, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	r, ok := h.series[q.Base]
+	var samples []HistorySample
+	if ok {
+		samples = r.all()
+	}
+	h.mu.RUnlock()
+
+	if !ok {
+		return nil, &NotFoundError{Currency: q.Base}
+	}
+
+	points := []HistoryPoint{}
+	for _, sample := range samples {
+		value, ok := sample.Rates[q.Quote]
+		if !ok || (!q.From.IsZero() && sample.Time.Before(q.From)) || (!q.To.IsZero() && sample.Time.After(q.To)) {
+			continue
+		}
+
+		if q.Interval == 0 {
+			points = append(points, HistoryPoint{Time: sample.Time, Value: value, Samples: 1})
+			continue
+		}
+
+		bucket := sample.Time.Truncate(q.Interval)
+		if len(points) == 0 || !points[len(points)-1].Time.Equal(bucket) {
+			points = append(points, HistoryPoint{Time: bucket, Open: value, High: value, Low: value})
+		}
+		point := &points[len(points)-1]
+		point.Samples++
+		point.Close = value
+		point.Value = point.Value.Add(value) // Sum until the bucket is finished
+		if value.Cmp(point.High) > 0 {
+			point.High = value
+		}
+		if value.Cmp(point.Low) < 0 {
+			point.Low = value
+		}
+	}
+
+	if q.Interval > 0 {
+		for i := range points {
+			points[i] = q.aggregate(points[i])
+		}
+	}
+	return points, nil
+}
+
+// aggregate turns a bucket holding the sum of its samples into a point of the query's aggregation
+func (q HistoryQuery) aggregate(point HistoryPoint) HistoryPoint {
+	switch q.Aggregation {
+	case AggregateAvg:
+		// The sample count is positive, so the division cannot fail
+		avg, _ := point.Value.Div(money.New(int64(point.Samples), 0), averagePlaces, money.HalfEven)
+		return HistoryPoint{Time: point.Time, Value: avg.Normalize(), Samples: point.Samples}
+	case AggregateOHLC:
+		point.Value = point.Close
+		return point
+	default:
+		return HistoryPoint{Time: point.Time, Value: point.Close, Samples: point.Samples}
+	}
+}
+
+func (q *HistoryQuery) validate() error {
+	if q.Base == "" {
+		return &QueryError{Field: "base", Message: "base currency is required"}
+	}
+	if q.Quote == "" {
+		return &QueryError{Field: "quote", Message: "quote currency is required"}
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.From.After(q.To) {
+		return &QueryError{Field: "from", Message: "from must not be after to"}
+	}
+	if q.Interval < 0 {
+		return &QueryError{Field: "interval", Message: "interval must not be negative"}
+	}
+	switch q.Aggregation {
+	case "":
+		q.Aggregation = AggregateLast
+	case AggregateLast, AggregateAvg, AggregateOHLC:
+	default:
+		return &QueryError{Field: "aggregation", Message: "aggregation must be last, avg or ohlc"}
+	}
+	return nil
+}
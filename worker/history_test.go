@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/money"
+)
+
+var historyStart = time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+
+// newTestHistory records USD/EUR samples one minute apart starting at historyStart
+func newTestHistory(size int, values ...string) *History {
+	h := NewHistory(size)
+	for i, value := range values {
+		h.Record(&RateData{
+			BaseCurrency: "USD",
+			Rates:        map[string]currencyapi.RateInfo{"EUR": {Code: "EUR", Value: money.MustParse(value)}},
+			FetchedAt:    historyStart.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return h
+}
+
+// pointValues returns the values of history points as strings
+func pointValues(points []HistoryPoint) []string {
+	values := make([]string, len(points))
+	for i, point := range points {
+		values[i] = point.Value.String()
+	}
+	return values
+}
+
+func TestHistoryQuery(t *testing.T) {
+	h := newTestHistory(10, "0.90", "0.94", "0.92", "0.91", "0.95", "0.93")
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		want  []string
+	}{
+		{
+			name:  "all samples",
+			query: HistoryQuery{Base: "USD", Quote: "EUR"},
+			want:  []string{"0.90", "0.94", "0.92", "0.91", "0.95", "0.93"},
+		},
+		{
+			name: "time range",
+			query: HistoryQuery{
+				Base:  "USD",
+				Quote: "EUR",
+				From:  historyStart.Add(time.Minute),
+				To:    historyStart.Add(3 * time.Minute),
+			},
+			want: []string{"0.94", "0.92", "0.91"},
+		},
+		{
+			name:  "last per bucket",
+			query: HistoryQuery{Base: "USD", Quote: "EUR", Interval: 3 * time.Minute},
+			want:  []string{"0.92", "0.93"},
+		},
+		{
+			name:  "average per bucket",
+			query: HistoryQuery{Base: "USD", Quote: "EUR", Interval: 3 * time.Minute, Aggregation: AggregateAvg},
+			want:  []string{"0.92", "0.93"},
+		},
+		{
+			name:  "average with remainder",
+			query: HistoryQuery{Base: "USD", Quote: "EUR", Interval: 2 * time.Minute, Aggregation: AggregateAvg},
+			want:  []string{"0.92", "0.915", "0.94"},
+		},
+		{
+			name:  "unknown quote",
+			query: HistoryQuery{Base: "USD", Quote: "GBP"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := h.Query(tt.query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			got := pointValues(points)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Query() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryOHLC(t *testing.T) {
+	h := newTestHistory(10, "0.90", "0.94", "0.92", "0.91", "0.95", "0.93")
+
+	points, err := h.Query(HistoryQuery{Base: "USD", Quote: "EUR", Interval: 3 * time.Minute, Aggregation: AggregateOHLC})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(points))
+	}
+
+	first := points[0]
+	if !first.Time.Equal(historyStart) || first.Samples != 3 {
+		t.Errorf("Unexpected bucket: %+v", first)
+	}
+	got := []string{first.Open.String(), first.High.String(), first.Low.String(), first.Close.String()}
+	want := []string{"0.90", "0.94", "0.90", "0.92"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("OHLC = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestHistoryBounded(t *testing.T) {
+	h := newTestHistory(3, "0.90", "0.91", "0.92", "0.93", "0.94")
+
+	points, err := h.Query(HistoryQuery{Base: "USD", Quote: "EUR"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := pointValues(points); len(got) != 3 || got[0] != "0.92" || got[2] != "0.94" {
+		t.Errorf("Expected the 3 newest samples oldest first, got %v", got)
+	}
+}
+
+func TestHistoryQueryErrors(t *testing.T) {
+	h := newTestHistory(10, "0.90")
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		field string
+	}{
+		{name: "missing quote", query: HistoryQuery{Base: "USD"}, field: "quote"},
+		{name: "inverted range", query: HistoryQuery{Base: "USD", Quote: "EUR", From: historyStart, To: historyStart.Add(-time.Hour)}, field: "from"},
+		{name: "negative interval", query: HistoryQuery{Base: "USD", Quote: "EUR", Interval: -time.Minute}, field: "interval"},
+		{name: "unknown aggregation", query: HistoryQuery{Base: "USD", Quote: "EUR", Aggregation: "median"}, field: "aggregation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.Query(tt.query)
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) || queryErr.Field != tt.field {
+				t.Errorf("Expected QueryError on %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	var notFoundErr *NotFoundError
+	if _, err := h.Query(HistoryQuery{Base: "GBP", Quote: "EUR"}); !errors.As(err, &notFoundErr) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+}
+
+func TestHistorySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	if err := newTestHistory(10, "0.90", "0.91", "0.92").Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A smaller size keeps only the newest samples
+	loaded, err := LoadHistory(path, 2)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	points, err := loaded.Query(HistoryQuery{Base: "USD", Quote: "EUR"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := pointValues(points); len(got) != 2 || got[0] != "0.91" || !points[1].Time.Equal(historyStart.Add(2*time.Minute)) {
+		t.Errorf("Unexpected loaded history: %+v", points)
+	}
+
+	if _, err := LoadHistory(filepath.Join(t.TempDir(), "missing.json"), 0); err != nil {
+		t.Errorf("LoadHistory() of a missing file error = %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// State is a lifecycle state of a Manager
+type State int
+
+// Manager lifecycle states. A manager moves from idle to running, and through
+// stopping to stopped; a stopped manager can be started again.
+const (
+	StateIdle State = iota
+	StateRunning
+	StateStopping
+	StateStopped
+)
+
+// stateSubscriberBuffer is the number of state changes buffered for a slow subscriber
+const stateSubscriberBuffer = 8
+
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// MarshalText encodes the state as its name
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Start starts all workers. The workers run until Stop is called or ctx is cancelled.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch m.state {
+	case StateIdle, StateStopped:
+	case StateStopping:
+		return errors.New("workers are stopping")
+	default:
+		return errors.New("workers are already running")
+	}
+
+	m.ctx = ctx
+	m.done = make(chan struct{})
+	m.workers = make(map[string]*runningWorker)
+	for _, worker := range m.newWorkers() {
+		m.startWorker(worker)
+	}
+	m.stopOnCancel = context.AfterFunc(ctx, func() {
+		_ = m.Stop(context.Background())
+	})
+
+	m.setState(StateRunning)
+	log.Println("All workers started")
+	return nil
+}
+
+// Stop signals all workers to stop and waits until they have, or until ctx is done.
+// If ctx ends first, the workers keep stopping in the background and Stop returns
+// the context error; the state changes to stopped once they are done.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	switch m.state {
+	case StateRunning:
+		log.Println("Stopping all workers...")
+		m.setState(StateStopping)
+		m.stopOnCancel()
+		for _, rw := range m.workers {
+			close(rw.stopCh)
+		}
+		go m.finishStop(m.done)
+	case StateStopping:
+		// Wait for the stop in progress
+	default:
+		m.mu.Unlock()
+		return nil
+	}
+	done := m.done
+	m.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("stopping workers: %w", ctx.Err())
+	}
+}
+
+// finishStop waits for the stopped workers and completes the transition to stopped
+func (m *Manager) finishStop(done chan struct{}) {
+	m.wg.Wait()
+
+	if m.config.HistoryFile != "" {
+		if err := m.history.Save(m.config.HistoryFile); err != nil {
+			log.Printf("Error saving rate history: %v", err)
+		}
+	}
+
+	m.mu.Lock()
+	m.workers = nil
+	m.setState(StateStopped)
+	m.mu.Unlock()
+
+	close(done)
+	log.Println("All workers stopped")
+}
+
+// Restart stops the workers, waiting at most until ctx is done, and starts them again
+// with the context of the previous Start. It fails without stopping anything when that
+// context is done, since the workers would exit right away; call Start with a new
+// context instead.
+func (m *Manager) Restart(ctx context.Context) error {
+	m.mu.RLock()
+	startCtx := m.ctx
+	m.mu.RUnlock()
+
+	if startCtx == nil {
+		return errors.New("workers have not been started")
+	}
+	if err := startCtx.Err(); err != nil {
+		return fmt.Errorf("workers were started with a context that is done: %w", err)
+	}
+	if err := m.Stop(ctx); err != nil {
+		return err
+	}
+	return m.Start(startCtx)
+}
+
+// State returns the lifecycle state of the manager
+func (m *Manager) State() State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// IsRunning returns whether the workers are running
+func (m *Manager) IsRunning() bool {
+	return m.State() == StateRunning
+}
+
+// StateChanges returns a channel receiving every state the manager moves to until ctx
+// is done, when the channel is closed. Changes are dropped for a subscriber that
+// falls more than a few changes behind.
+func (m *Manager) StateChanges(ctx context.Context) <-chan State {
+	ch := make(chan State, stateSubscriberBuffer)
+
+	m.mu.Lock()
+	m.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		m.mu.Lock()
+		delete(m.subscribers, ch)
+		m.mu.Unlock()
+		close(ch)
+	})
+	return ch
+}
+
+// setState changes the state and notifies the subscribers; m.mu must be held
+func (m *Manager) setState(state State) {
+	m.state = state
+	for ch := range m.subscribers {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+)
+
+// expectStates reads states from a state-change channel and compares them with want
+func expectStates(t *testing.T, ch <-chan State, want ...State) {
+	t.Helper()
+	for _, state := range want {
+		select {
+		case got := <-ch:
+			if got != state {
+				t.Fatalf("Expected state %v, got %v", state, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for state %v", state)
+		}
+	}
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD", "EUR"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if manager.State() != StateIdle {
+		t.Fatalf("Expected idle manager, got %v", manager.State())
+	}
+	if err := manager.Restart(context.Background()); err == nil {
+		t.Error("Restart() before Start() should fail")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	states := manager.StateChanges(ctx)
+
+	for i := 0; i < 5; i++ {
+		if err := manager.Start(context.Background()); err != nil {
+			t.Fatalf("Start() #%d error = %v", i, err)
+		}
+		expectStates(t, states, StateRunning)
+		if got := len(manager.workers); got != 2 {
+			t.Fatalf("Expected 2 workers after Start() #%d, got %d", i, got)
+		}
+
+		if err := manager.Stop(context.Background()); err != nil {
+			t.Fatalf("Stop() #%d error = %v", i, err)
+		}
+		expectStates(t, states, StateStopping, StateStopped)
+		if manager.IsRunning() {
+			t.Fatalf("Expected stopped manager after Stop() #%d", i)
+		}
+	}
+
+	// Every cycle fetched again, so the workers ran instead of exiting right away
+	if got := fake.CallCount(currencyapitest.EndpointLatest); got != 10 {
+		t.Errorf("Expected one fetch per worker and cycle, got %d", got)
+	}
+
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() of a stopped manager error = %v", err)
+	}
+
+	cancel()
+	if _, ok := <-states; ok {
+		t.Error("Expected the state channel to be closed")
+	}
+}
+
+func TestManagerRestart(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+
+	// Concurrent restarts and reads must be safe; some restarts lose the race to start
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 5; j++ {
+				_ = manager.Restart(context.Background())
+				_ = manager.GetCurrencies()
+				_ = manager.State()
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+
+	if manager.State() != StateRunning {
+		if err := manager.Start(context.Background()); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	}
+	if !manager.IsRunning() {
+		t.Errorf("Expected running manager, got %v", manager.State())
+	}
+}
+
+func TestManagerStopDeadline(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	fake.SetLatency(currencyapitest.EndpointLatest, 200*time.Millisecond)
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	states := manager.StateChanges(ctx)
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	expectStates(t, states, StateRunning)
+
+	// The worker is in the middle of a slow fetch and cannot stop in time
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stopCancel()
+	if err := manager.Stop(stopCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline error, got %v", err)
+	}
+	if err := manager.Start(context.Background()); err == nil {
+		t.Error("Start() while stopping should fail")
+	}
+
+	// The stop completes in the background
+	expectStates(t, states, StateStopping, StateStopped)
+	if err := manager.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestManagerStopsWhenContextCancelled(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	states := manager.StateChanges(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := manager.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	cancel()
+
+	expectStates(t, states, StateRunning, StateStopping, StateStopped)
+}
+
+func TestManagerRestartAfterCancel(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	states := manager.StateChanges(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := manager.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	cancel()
+	expectStates(t, states, StateRunning, StateStopping, StateStopped)
+
+	if err := manager.Restart(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Restart() error = %v, want context.Canceled", err)
+	}
+	if got := manager.State(); got != StateStopped {
+		t.Errorf("Expected stopped manager, got %v", got)
+	}
+
+	// A new context starts the workers again
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+	if !manager.IsRunning() {
+		t.Errorf("Expected running manager, got %v", manager.State())
+	}
+}
@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+)
+
+// Clock tells the time and waits for the worker scheduler; tests replace it to control time
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock of the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// BackoffPolicy configures how a worker slows down after failed fetches.
+// Temporary errors stretch the fetch interval exponentially, a Retry-After hint pauses
+// the worker for the requested time and an exhausted quota suspends it until the next
+// month (UTC). The first successful fetch restores the normal interval.
+type BackoffPolicy struct {
+	// MaxBackoff caps the delay after temporary errors; it never shortens the interval (default: 1h)
+	MaxBackoff time.Duration
+	// Multiplier is the growth factor applied to the interval after each temporary error (default: 2)
+	Multiplier float64
+	// Jitter is the fraction of the interval that is randomized, between 0 and 1,
+	// so that workers don't fetch in lockstep (default: 0.1)
+	Jitter float64
+}
+
+// DefaultBackoffPolicy returns a backoff policy with sensible defaults
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{
+		MaxBackoff: time.Hour,
+		Multiplier: 2,
+		Jitter:     0.1,
+	}
+}
+
+// withDefaults replaces invalid fields with the values from DefaultBackoffPolicy
+func (p BackoffPolicy) withDefaults() BackoffPolicy {
+	defaults := DefaultBackoffPolicy()
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = defaults.Jitter
+	}
+	return p
+}
+
+// scheduler decides when a worker fetches next from the outcome of its fetches
+type scheduler struct {
+	policy   BackoffPolicy
+	clock    Clock
+	random   func() float64
+	failures int       // Consecutive temporary failures
+	resumeAt time.Time // Set while paused by Retry-After or an exhausted quota
+}
+
+func newScheduler(policy BackoffPolicy, clock Clock) *scheduler {
+	return &scheduler{
+		policy: policy.withDefaults(),
+		clock:  clock,
+		random: rand.Float64,
+	}
+}
+
+// record updates the schedule with the outcome of a fetch
+func (s *scheduler) record(err error) {
+	s.resumeAt = time.Time{}
+	if err == nil {
+		s.failures = 0
+		return
+	}
+
+	if currencyapi.IsQuotaExceededError(err) {
+		s.resumeAt = startOfNextMonth(s.clock.Now())
+		return
+	}
+	if retryAfter, ok := currencyapi.GetRetryAfter(err); ok {
+		s.failures++
+		s.resumeAt = s.clock.Now().Add(retryAfter)
+		return
+	}
+	if currencyapi.IsTemporaryError(err) {
+		s.failures++
+		return
+	}
+	// Permanent errors won't go away by waiting longer
+	s.failures = 0
+}
+
+// delay returns how long to wait before the next fetch
+func (s *scheduler) delay(interval time.Duration) time.Duration {
+	if !s.resumeAt.IsZero() {
+		// Only delay a pause further, its end is the earliest time the API accepts requests
+		wait := max(s.resumeAt.Sub(s.clock.Now()), 0)
+		return wait + time.Duration(float64(interval)*s.policy.Jitter*s.random())
+	}
+
+	delay := float64(interval)
+	if s.failures > 0 {
+		delay *= math.Pow(s.policy.Multiplier, float64(s.failures))
+		delay = min(delay, float64(max(s.policy.MaxBackoff, interval)))
+	}
+	// Spread the delay uniformly over [delay*(1-jitter), delay*(1+jitter)]
+	delay += delay * s.policy.Jitter * (2*s.random() - 1)
+	return time.Duration(delay)
+}
+
+// backingOff reports whether the next fetch is delayed because of failures
+func (s *scheduler) backingOff() bool {
+	return s.failures > 0 || !s.resumeAt.IsZero()
+}
+
+// startOfNextMonth returns midnight UTC of the first day of the month after t
+func startOfNextMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
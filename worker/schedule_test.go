@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
+)
+
+// fakeClock is a Clock that only moves when advanced
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and fires the waiters that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// waitForWaiter blocks until someone waits on the clock
+func (c *fakeClock) waitForWaiter(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		waiting := len(c.waiters) > 0
+		c.mu.Unlock()
+		if waiting {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the worker to schedule a fetch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerBackoff(t *testing.T) {
+	start := time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+
+	unavailable := &currencyapi.HTTPError{StatusCode: 503}
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	fake.FailNext(currencyapitest.EndpointLatest,
+		unavailable, unavailable, unavailable, unavailable,
+		&currencyapi.HTTPError{StatusCode: 429, RetryAfter: 90 * time.Second},
+		&currencyapi.APIError{StatusCode: 429, Code: "quota_exceeded"},
+	)
+
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Minute,
+		Backoff:       &BackoffPolicy{MaxBackoff: 5 * time.Minute, Multiplier: 2},
+		Clock:         clock,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Stop(context.Background()) })
+
+	midnight := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []struct {
+		name string
+		want time.Duration // Delay after the previous fetch
+	}{
+		{name: "first failure", want: 2 * time.Minute},
+		{name: "second failure", want: 4 * time.Minute},
+		{name: "capped", want: 5 * time.Minute},
+		{name: "still capped", want: 5 * time.Minute},
+		{name: "retry after", want: 90 * time.Second},
+		{name: "quota exceeded", want: midnight.Sub(start.Add(16*time.Minute + 90*time.Second))},
+		{name: "recovered", want: time.Minute},
+		{name: "normal cadence", want: time.Minute},
+	}
+	for i, step := range steps {
+		clock.waitForWaiter(t)
+		next := manager.GetWorkerStatus().Workers["USD"].NextRun
+		if got := next.Sub(clock.Now()); got != step.want {
+			t.Fatalf("%s: next fetch in %v, want %v", step.name, got, step.want)
+		}
+		clock.Advance(step.want)
+
+		// The fetch must have happened before the next step
+		deadline := time.Now().Add(2 * time.Second)
+		for fake.CallCount(currencyapitest.EndpointLatest) < i+2 {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: timed out waiting for the fetch", step.name)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if got := clock.Now(); !got.Equal(midnight.Add(2 * time.Minute)) {
+		t.Errorf("Expected the quota to pause the worker until midnight, clock is at %v", got)
+	}
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC))
+	s := newScheduler(BackoffPolicy{Jitter: 0.1}, clock)
+
+	tests := []struct {
+		name   string
+		random float64
+		err    error
+		want   time.Duration
+	}{
+		{name: "earliest", random: 0, want: 54 * time.Second},
+		{name: "latest", random: 1, want: 66 * time.Second},
+		{name: "backoff", random: 0, err: &currencyapi.HTTPError{StatusCode: 503}, want: 108 * time.Second},
+		{name: "pause is never shortened", random: 0, err: &currencyapi.HTTPError{StatusCode: 429, RetryAfter: time.Minute}, want: time.Minute},
+		{name: "pause", random: 1, err: &currencyapi.HTTPError{StatusCode: 429, RetryAfter: time.Minute}, want: 66 * time.Second},
+		{name: "permanent error", random: 1, err: &currencyapi.APIError{StatusCode: 401}, want: 66 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.record(tt.err)
+			s.random = func() float64 { return tt.random }
+			if got := s.delay(time.Minute); got != tt.want {
+				t.Errorf("delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
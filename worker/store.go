@@ -0,0 +1,90 @@
+package worker
+
+import "sync"
+
+// RateStore stores the latest rate data per base currency. Implementations must be safe for concurrent use.
+type RateStore interface {
+	// Set stores rate data for a currency
+	Set(currency string, data *RateData) error
+	// Get retrieves rate data for a currency or returns a *NotFoundError
+	Get(currency string) (*RateData, error)
+	// Delete removes the rate data of a currency
+	Delete(currency string) error
+	// GetAll returns a copy of all stored rate data
+	GetAll() map[string]*RateData
+	// Close releases the resources of the store
+	Close() error
+}
+
+// MemoryStore is a thread-safe in-memory storage for currency rates
+type MemoryStore struct {
+	data map[string]*RateData
+	mu   sync.RWMutex
+}
+
+// NewMemoryStore creates a new in-memory rate store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		data: make(map[string]*RateData),
+	}
+}
+
+// NewRateStore creates a new in-memory rate store
+func NewRateStore() *MemoryStore {
+	return NewMemoryStore()
+}
+
+// Set stores rate data for a currency
+func (s *MemoryStore) Set(currency string, data *RateData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[currency] = data
+	return nil
+}
+
+// Get retrieves rate data for a currency
+func (s *MemoryStore) Get(currency string) (*RateData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.data[currency]
+	if !ok {
+		return nil, &NotFoundError{Currency: currency}
+	}
+	return data, nil
+}
+
+// Delete removes the rate data of a currency
+func (s *MemoryStore) Delete(currency string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, currency)
+	return nil
+}
+
+// GetAll returns all stored rate data
+func (s *MemoryStore) GetAll() map[string]*RateData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Return a copy to avoid race conditions
+	result := make(map[string]*RateData, len(s.data))
+	for k, v := range s.data {
+		result[k] = v
+	}
+	return result
+}
+
+// Close does nothing for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// NotFoundError is returned when rate data is not found for a currency
+type NotFoundError struct {
+	Currency string
+}
+
+func (e *NotFoundError) Error() string {
+	return "rates not found for currency: " + e.Currency
+}
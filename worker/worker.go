@@ -5,7 +5,9 @@ package worker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
@@ -17,7 +19,16 @@ type RateData struct {
 	BaseCurrency  string                          `json:"base_currency"`
 	Rates         map[string]currencyapi.RateInfo `json:"rates"`
 	LastUpdatedAt string                          `json:"last_updated_at"`
+	Provider      string                          `json:"provider,omitempty"`
 	FetchedAt     time.Time                       `json:"fetched_at"`
+	// Derived is set for rates computed from the pivot currency rather than fetched
+	Derived bool `json:"derived,omitempty"`
+	// Pivot is the base currency the derived rates were computed from
+	Pivot string `json:"pivot,omitempty"`
+	// SourceFetchedAt is when the pivot rates the derived rates were computed from were fetched
+	SourceFetchedAt time.Time `json:"source_fetched_at,omitzero"`
+	// Stale is set for rates loaded from a previous run that have not been refreshed yet
+	Stale bool `json:"stale,omitempty"`
 }
 
 // Config holds the configuration for the worker manager
@@ -28,6 +39,21 @@ type Config struct {
 	FetchInterval time.Duration
 	// RequestTimeout is the timeout for individual API requests (default: 10 seconds)
 	RequestTimeout time.Duration
+	// Pivot enables triangulation: only the pivot currency is fetched and the rates of
+	// the other currencies are derived from it, spending one API call per interval (optional)
+	Pivot string
+	// Store keeps the fetched rates; a persistent store serves the rates of the previous
+	// run as stale until they are refreshed (default: in-memory store)
+	Store RateStore
+	// HistorySize is the number of fetched samples kept per base currency (default: 1440)
+	HistorySize int
+	// HistoryFile persists the rate history across restarts; it is loaded by NewManager
+	// and saved by Stop (optional)
+	HistoryFile string
+	// Backoff controls how workers slow down after failed fetches (default: DefaultBackoffPolicy)
+	Backoff *BackoffPolicy
+	// Clock is used to schedule fetches and timestamp rates (default: the system clock)
+	Clock Clock
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -39,16 +65,37 @@ func DefaultConfig() Config {
 	}
 }
 
+// Errors returned when changing the tracked currencies
+var (
+	ErrInvalidCurrency = errors.New("invalid currency code")
+	ErrAlreadyTracked  = errors.New("currency is already tracked")
+	ErrNotTracked      = errors.New("currency is not tracked")
+	ErrPivotCurrency   = errors.New("the pivot currency cannot be removed")
+)
+
 // Manager manages multiple currency rate workers
 type Manager struct {
 	config    Config
 	apiClient currencyapi.Client
-	store     *RateStore
-	workers   []*Worker
-	stopCh    chan struct{}
+	store     RateStore
+	history   *History
+	events    *eventHub
+	workers   map[string]*runningWorker // Started workers by base currency
+	ctx       context.Context           // Context of Start, also used by workers added later
 	wg        sync.WaitGroup
-	running   bool
 	mu        sync.RWMutex
+
+	state        State
+	done         chan struct{} // Closed when the workers of the last Start have stopped
+	stopOnCancel func() bool   // Unregisters the stop on cancellation of the Start context
+	subscribers  map[chan State]struct{}
+}
+
+// runningWorker is a started worker with the channels to stop it
+type runningWorker struct {
+	worker *Worker
+	stopCh chan struct{}
+	done   chan struct{}
 }
 
 // NewManager creates a new worker manager
@@ -67,55 +114,164 @@ func NewManager(apiClient currencyapi.Client, cfg Config) (*Manager, error) {
 	if cfg.RequestTimeout == 0 {
 		cfg.RequestTimeout = defaults.RequestTimeout
 	}
+	// The currencies change at runtime, so don't share the caller's slice
+	cfg.Currencies = slices.Clone(cfg.Currencies)
+	if cfg.Pivot != "" && !slices.Contains(cfg.Currencies, cfg.Pivot) {
+		cfg.Currencies = append(cfg.Currencies, cfg.Pivot)
+	}
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	if loaded := len(store.GetAll()); loaded > 0 {
+		log.Printf("Warm start: serving stale rates of %d currencies until they are refreshed", loaded)
+	}
+
+	history := NewHistory(cfg.HistorySize)
+	if cfg.HistoryFile != "" {
+		var err error
+		if history, err = LoadHistory(cfg.HistoryFile, cfg.HistorySize); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Manager{
-		config:    cfg,
-		apiClient: apiClient,
-		store:     NewRateStore(),
-		stopCh:    make(chan struct{}),
+		config:      cfg,
+		apiClient:   apiClient,
+		store:       store,
+		history:     history,
+		subscribers: make(map[chan State]struct{}),
+		events:      newEventHub(),
 	}, nil
 }
 
-// Start starts all workers
-func (m *Manager) Start(ctx context.Context) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// newWorkers creates one worker per currency, or a single pivot worker in triangulation mode
+func (m *Manager) newWorkers() []*Worker {
+	if m.config.Pivot == "" {
+		log.Printf("Starting %d currency rate workers", len(m.config.Currencies))
+		workers := make([]*Worker, 0, len(m.config.Currencies))
+		for _, currency := range m.config.Currencies {
+			workers = append(workers, NewWorker(currency, m.apiClient, m.store, m.config))
+		}
+		return workers
+	}
 
-	if m.running {
-		return errors.New("workers are already running")
+	log.Printf("Starting %s pivot worker for %d currencies", m.config.Pivot, len(m.config.Currencies))
+	worker := NewWorker(m.config.Pivot, m.apiClient, m.store, m.config)
+	for _, currency := range m.config.Currencies {
+		if currency != m.config.Pivot {
+			worker.derived = append(worker.derived, currency)
+		}
 	}
+	return []*Worker{worker}
+}
 
-	log.Printf("Starting %d currency rate workers", len(m.config.Currencies))
+// startWorker runs a worker until it is stopped; m.mu must be held
+func (m *Manager) startWorker(worker *Worker) {
+	worker.history = m.history
+	worker.events = m.events
+	rw := &runningWorker{
+		worker: worker,
+		stopCh: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	m.workers[worker.baseCurrency] = rw
+
+	ctx := m.ctx
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer close(rw.done)
+		worker.Run(ctx, rw.stopCh)
+	}()
+}
 
-	for _, currency := range m.config.Currencies {
-		worker := NewWorker(currency, m.apiClient, m.store, m.config)
-		m.workers = append(m.workers, worker)
+// AddCurrency starts tracking a base currency. If the workers are running, a worker for
+// the currency is started, or in triangulation mode its rates are derived from the pivot.
+func (m *Manager) AddCurrency(currency string) error {
+	if !IsCurrencyCode(currency) {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
 
-		m.wg.Add(1)
-		go func(w *Worker) {
-			defer m.wg.Done()
-			w.Run(ctx, m.stopCh)
-		}(worker)
+	m.mu.Lock()
+	if slices.Contains(m.config.Currencies, currency) {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrAlreadyTracked, currency)
 	}
+	m.config.Currencies = append(m.config.Currencies, currency)
 
-	m.running = true
-	log.Println("All workers started")
+	if m.state != StateRunning {
+		m.mu.Unlock()
+		return nil
+	}
+	if m.config.Pivot == "" {
+		m.startWorker(NewWorker(currency, m.apiClient, m.store, m.config))
+		m.mu.Unlock()
+		return nil
+	}
+	pivot := m.workers[m.config.Pivot].worker
+	pivot.addDerived(currency)
+	m.mu.Unlock()
+
+	// Derive right away from the last pivot rates instead of waiting for the next fetch.
+	// This runs outside the lock, since storing may sync to disk and publishes an event.
+	if source, err := m.store.Get(pivot.baseCurrency); err == nil && !source.Stale {
+		pivot.deriveCurrency(source, currency)
+	}
+	log.Printf("[%s] Deriving rates for %s", pivot.baseCurrency, currency)
 	return nil
 }
 
-// Stop stops all workers gracefully
-func (m *Manager) Stop() {
+// RemoveCurrency stops tracking a base currency, stops its worker and deletes its stored rates.
+// The rate history of the currency is kept.
+func (m *Manager) RemoveCurrency(currency string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	i := slices.Index(m.config.Currencies, currency)
+	if i < 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNotTracked, currency)
+	}
+	if currency == m.config.Pivot {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrPivotCurrency, currency)
+	}
+	m.config.Currencies = slices.Delete(m.config.Currencies, i, i+1)
+
+	var stopped *runningWorker
+	if m.state == StateRunning {
+		if m.config.Pivot != "" {
+			m.workers[m.config.Pivot].worker.removeDerived(currency)
+		} else {
+			stopped = m.workers[currency]
+			delete(m.workers, currency)
+			close(stopped.stopCh)
+		}
+	}
+	m.mu.Unlock()
 
-	if !m.running {
-		return
+	// Wait outside the lock, a fetch in progress may take up to the request timeout,
+	// so that the worker doesn't store rates after they are deleted
+	if stopped != nil {
+		<-stopped.done
 	}
+	return m.store.Delete(currency)
+}
+
+// SetInterval changes the interval between fetches of all workers, including running ones
+func (m *Manager) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("fetch interval must be positive")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	log.Println("Stopping all workers...")
-	close(m.stopCh)
-	m.wg.Wait()
-	m.running = false
-	log.Println("All workers stopped")
+	m.config.FetchInterval = interval
+	for _, rw := range m.workers {
+		rw.worker.setInterval(interval)
+	}
+	log.Printf("Fetch interval set to %v", interval)
+	return nil
 }
 
 // GetRates returns the cached rates for a specific base currency
@@ -123,6 +279,11 @@ func (m *Manager) GetRates(baseCurrency string) (*RateData, error) {
 	return m.store.Get(baseCurrency)
 }
 
+// GetHistory returns the rate history of a currency pair
+func (m *Manager) GetHistory(query HistoryQuery) ([]HistoryPoint, error) {
+	return m.history.Query(query)
+}
+
 // GetAllRates returns all cached rates
 func (m *Manager) GetAllRates() map[string]*RateData {
 	return m.store.GetAll()
@@ -130,45 +291,96 @@ func (m *Manager) GetAllRates() map[string]*RateData {
 
 // GetCurrencies returns the list of currencies being tracked
 func (m *Manager) GetCurrencies() []string {
-	return m.config.Currencies
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.config.Currencies)
 }
 
-// IsRunning returns whether the workers are running
-func (m *Manager) IsRunning() bool {
+// GetInterval returns the interval between fetches
+func (m *Manager) GetInterval() time.Duration {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.running
+	return m.config.FetchInterval
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 currency code
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
 }
 
 // Worker fetches rates for a specific base currency
 type Worker struct {
 	baseCurrency string
 	apiClient    currencyapi.Client
-	store        *RateStore
+	store        RateStore
 	config       Config
+	history      *History  // Records every stored fetch (optional)
+	events       *eventHub // Publishes every stored fetch (optional)
+	interval     chan time.Duration
+	health       healthTracker
+	clock        Clock
+	schedule     *scheduler // Only used by Run
+
+	derived []string // Base currencies derived from this worker's rates
+	mu      sync.Mutex
 }
 
 // NewWorker creates a new worker for a specific currency
-func NewWorker(baseCurrency string, apiClient currencyapi.Client, store *RateStore, cfg Config) *Worker {
+func NewWorker(baseCurrency string, apiClient currencyapi.Client, store RateStore, cfg Config) *Worker {
+	clock := cfg.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	policy := DefaultBackoffPolicy()
+	if cfg.Backoff != nil {
+		policy = *cfg.Backoff
+	}
+
 	return &Worker{
 		baseCurrency: baseCurrency,
 		apiClient:    apiClient,
 		store:        store,
 		config:       cfg,
+		interval:     make(chan time.Duration, 1),
+		clock:        clock,
+		schedule:     newScheduler(policy, clock),
+	}
+}
+
+// setInterval changes the interval between fetches; calls must not be concurrent
+func (w *Worker) setInterval(interval time.Duration) {
+	// Replace a change the worker hasn't picked up yet
+	select {
+	case <-w.interval:
+	default:
 	}
+	w.interval <- interval
 }
 
-// Run starts the worker's fetch loop
+// Run starts the worker's fetch loop. Fetches follow the fetch interval with some jitter,
+// and are delayed after failures as configured by the backoff policy.
 func (w *Worker) Run(ctx context.Context, stopCh <-chan struct{}) {
 	log.Printf("[%s] Worker started", w.baseCurrency)
 
 	// Fetch immediately on start
-	w.fetch(ctx)
-
-	ticker := time.NewTicker(w.config.FetchInterval)
-	defer ticker.Stop()
+	w.schedule.record(w.fetch(ctx))
 
+	interval := w.config.FetchInterval
 	for {
+		delay := w.schedule.delay(interval)
+		if w.schedule.backingOff() {
+			log.Printf("[%s] Backing off, next fetch in %v", w.baseCurrency, delay.Round(time.Second))
+		}
+		w.health.scheduleNext(w.clock.Now().Add(delay))
+
 		select {
 		case <-ctx.Done():
 			log.Printf("[%s] Worker stopped: context cancelled", w.baseCurrency)
@@ -176,88 +388,117 @@ func (w *Worker) Run(ctx context.Context, stopCh <-chan struct{}) {
 		case <-stopCh:
 			log.Printf("[%s] Worker stopped: stop signal received", w.baseCurrency)
 			return
-		case <-ticker.C:
-			w.fetch(ctx)
+		case <-w.clock.After(delay):
+			w.schedule.record(w.fetch(ctx))
+		case interval = <-w.interval:
+			// Reschedule with the new interval
 		}
 	}
 }
 
-// fetch fetches the latest rates and stores them
-func (w *Worker) fetch(ctx context.Context) {
-	fetchCtx, cancel := context.WithTimeout(ctx, w.config.RequestTimeout)
+// fetch fetches the latest rates and stores them, returning the fetch error
+func (w *Worker) fetch(ctx context.Context) error {
+	// Background refreshes are essential and may spend the reserved quota
+	fetchCtx, cancel := context.WithTimeout(currencyapi.WithEssential(ctx), w.config.RequestTimeout)
 	defer cancel()
 
 	log.Printf("[%s] Fetching latest rates...", w.baseCurrency)
 
+	start := w.clock.Now()
 	response, err := w.apiClient.Latest(fetchCtx, &currencyapi.LatestParams{
 		BaseCurrency: w.baseCurrency,
 	})
+	now := w.clock.Now()
 	if err != nil {
+		w.health.recordFailure(now, now.Sub(start), err)
 		log.Printf("[%s] Error fetching rates: %v", w.baseCurrency, err)
-		return
+		return err
 	}
+	w.health.recordSuccess(now, now.Sub(start))
 
 	rateData := &RateData{
 		BaseCurrency:  w.baseCurrency,
 		Rates:         response.Data,
 		LastUpdatedAt: response.Meta.LastUpdatedAt,
-		FetchedAt:     time.Now(),
+		Provider:      response.Meta.Provider,
+		FetchedAt:     now,
 	}
 
-	w.store.Set(w.baseCurrency, rateData)
+	w.save(rateData)
 	log.Printf("[%s] Updated rates: %d currencies", w.baseCurrency, len(response.Data))
-}
 
-// RateStore is a thread-safe storage for currency rates
-type RateStore struct {
-	data map[string]*RateData
-	mu   sync.RWMutex
+	w.derive(rateData)
+	return nil
 }
 
-// NewRateStore creates a new rate store
-func NewRateStore() *RateStore {
-	return &RateStore{
-		data: make(map[string]*RateData),
+// save records rate data in the history, stores it and publishes the change
+func (w *Worker) save(data *RateData) {
+	if w.history != nil {
+		w.history.Record(data)
+	}
+	previous, _ := w.store.Get(data.BaseCurrency)
+	if err := w.store.Set(data.BaseCurrency, data); err != nil {
+		log.Printf("[%s] Error storing %s rates: %v", w.baseCurrency, data.BaseCurrency, err)
+		return
+	}
+	if w.events != nil {
+		w.events.publish(newRateUpdated(previous, data))
 	}
 }
 
-// Set stores rate data for a currency
-func (s *RateStore) Set(currency string, data *RateData) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.data[currency] = data
-}
-
-// Get retrieves rate data for a currency
-func (s *RateStore) Get(currency string) (*RateData, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// derive computes and stores the rates of the derived base currencies from the fetched rates
+func (w *Worker) derive(source *RateData) {
+	// Hold the lock while storing, so that a removed currency isn't stored afterwards
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	data, ok := s.data[currency]
-	if !ok {
-		return nil, &NotFoundError{Currency: currency}
+	for _, currency := range w.derived {
+		w.deriveLocked(source, currency)
+	}
+	if len(w.derived) > 0 {
+		log.Printf("[%s] Derived rates for %d currencies", w.baseCurrency, len(w.derived))
 	}
-	return data, nil
 }
 
-// GetAll returns all stored rate data
-func (s *RateStore) GetAll() map[string]*RateData {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// deriveCurrency computes and stores the rates of one derived base currency, unless it
+// has been removed in the meantime
+func (w *Worker) deriveCurrency(source *RateData, currency string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if slices.Contains(w.derived, currency) {
+		w.deriveLocked(source, currency)
+	}
+}
 
-	// Return a copy to avoid race conditions
-	result := make(map[string]*RateData, len(s.data))
-	for k, v := range s.data {
-		result[k] = v
+func (w *Worker) deriveLocked(source *RateData, currency string) {
+	rates, err := currencyapi.CrossRates(source.Rates, w.baseCurrency, currency, nil)
+	if err != nil {
+		log.Printf("[%s] Error deriving %s rates: %v", w.baseCurrency, currency, err)
+		return
 	}
-	return result
+
+	w.save(&RateData{
+		BaseCurrency:    currency,
+		Rates:           rates,
+		LastUpdatedAt:   source.LastUpdatedAt,
+		Provider:        source.Provider,
+		FetchedAt:       w.clock.Now(),
+		Derived:         true,
+		Pivot:           w.baseCurrency,
+		SourceFetchedAt: source.FetchedAt,
+	})
 }
 
-// NotFoundError is returned when rate data is not found for a currency
-type NotFoundError struct {
-	Currency string
+// addDerived starts deriving the rates of a base currency
+func (w *Worker) addDerived(currency string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.derived = append(w.derived, currency)
 }
 
-func (e *NotFoundError) Error() string {
-	return "rates not found for currency: " + e.Currency
+// removeDerived stops deriving the rates of a base currency
+func (w *Worker) removeDerived(currency string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.derived = slices.DeleteFunc(w.derived, func(c string) bool { return c == currency })
 }
@@ -2,8 +2,13 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/BohdanKyryliuk/golang/currencyapi"
+	"github.com/BohdanKyryliuk/golang/currencyapi/currencyapitest"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -75,9 +80,143 @@ func TestNotFoundError(t *testing.T) {
 }
 
 func TestManagerStartStop(t *testing.T) {
-	// This is an integration test that would require a mock API client
-	// For now, we just test the basic flow
-	t.Skip("Skipping integration test - requires mock API client")
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD", "EUR"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err == nil {
+		t.Error("Second Start() should fail while running")
+	}
+	if !manager.IsRunning() {
+		t.Error("Expected manager to be running")
+	}
+
+	waitForRates(t, manager, "USD", "EUR")
+
+	rates, err := manager.GetRates("EUR")
+	if err != nil {
+		t.Fatalf("GetRates() error = %v", err)
+	}
+	if rates.BaseCurrency != "EUR" || rates.Rates["EUR"].Value.String() != "1" {
+		t.Errorf("Unexpected EUR rates: %+v", rates)
+	}
+
+	manager.Stop(context.Background())
+	if manager.IsRunning() {
+		t.Error("Expected manager to be stopped")
+	}
+	if got := fake.CallCount(currencyapitest.EndpointLatest); got != 2 {
+		t.Errorf("Expected one fetch per currency, got %d", got)
+	}
+}
+
+func TestWorkerKeepsRatesOnError(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	store := NewRateStore()
+	worker := NewWorker("USD", fake, store, DefaultConfig())
+
+	worker.fetch(context.Background())
+	before, err := store.Get("USD")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	fake.FailNext(currencyapitest.EndpointLatest, &currencyapi.HTTPError{StatusCode: 503})
+	worker.fetch(context.Background())
+
+	after, err := store.Get("USD")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if after != before {
+		t.Error("Failed fetch must not replace stored rates")
+	}
+}
+
+func TestWorkerBypassesCache(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	cached, err := currencyapi.NewCachingClient(fake, currencyapi.CacheOptions{LatestTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewCachingClient() error = %v", err)
+	}
+	worker := NewWorker("USD", cached, NewRateStore(), DefaultConfig())
+
+	// Both fetches reach the upstream client although the first response is still cached
+	for i := range 2 {
+		worker.fetch(context.Background())
+		if got := fake.CallCount(currencyapitest.EndpointLatest); got != i+1 {
+			t.Errorf("Fetch %d didn't reach the upstream client: %d calls", i+1, got)
+		}
+	}
+}
+
+func TestManagerTriangulation(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"EUR", "GBP"},
+		FetchInterval: time.Hour,
+		Pivot:         "USD",
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	waitForRates(t, manager, "EUR", "GBP", "USD")
+	manager.Stop(context.Background())
+
+	if got := fake.CallCount(currencyapitest.EndpointLatest); got != 1 {
+		t.Errorf("Expected a single pivot fetch, got %d", got)
+	}
+
+	pivot, err := manager.GetRates("USD")
+	if err != nil {
+		t.Fatalf("GetRates(USD) error = %v", err)
+	}
+	if pivot.Derived || pivot.Pivot != "" {
+		t.Errorf("Pivot rates must not be marked as derived: %+v", pivot)
+	}
+
+	rates, err := manager.GetRates("EUR")
+	if err != nil {
+		t.Fatalf("GetRates(EUR) error = %v", err)
+	}
+	if !rates.Derived || rates.Pivot != "USD" || !rates.SourceFetchedAt.Equal(pivot.FetchedAt) {
+		t.Errorf("Unexpected derived metadata: %+v", rates)
+	}
+	if rates.LastUpdatedAt != pivot.LastUpdatedAt {
+		t.Errorf("Expected source timestamp %q, got %q", pivot.LastUpdatedAt, rates.LastUpdatedAt)
+	}
+	if got := rates.Rates["USD"].Value.String(); got != "1.086956521739130435" {
+		t.Errorf("Expected derived EUR/USD 1.086956521739130435, got %s", got)
+	}
+	if got := rates.Rates["EUR"].Value.String(); got != "1" {
+		t.Errorf("Expected EUR/EUR 1, got %s", got)
+	}
+}
+
+// waitForRates waits until the manager has stored rates for all currencies
+func waitForRates(t *testing.T, manager *Manager, currencies ...string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(manager.GetAllRates()) >= len(currencies) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for rates of %v", currencies)
 }
 
 // Helper function for error type checking
@@ -164,3 +303,189 @@ func TestRateStoreThreadSafety(t *testing.T) {
 	<-done
 	<-done
 }
+
+func TestManagerAddRemoveCurrency(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+	waitForRates(t, manager, "USD")
+
+	if err := manager.AddCurrency("JPY"); err != nil {
+		t.Fatalf("AddCurrency() error = %v", err)
+	}
+	waitForRates(t, manager, "USD", "JPY")
+	if got := manager.GetCurrencies(); len(got) != 2 || got[1] != "JPY" {
+		t.Errorf("GetCurrencies() = %v", got)
+	}
+
+	if err := manager.AddCurrency("JPY"); !errors.Is(err, ErrAlreadyTracked) {
+		t.Errorf("Expected ErrAlreadyTracked, got %v", err)
+	}
+	if err := manager.AddCurrency("yen"); !errors.Is(err, ErrInvalidCurrency) {
+		t.Errorf("Expected ErrInvalidCurrency, got %v", err)
+	}
+
+	if err := manager.RemoveCurrency("USD"); err != nil {
+		t.Fatalf("RemoveCurrency() error = %v", err)
+	}
+	if _, err := manager.GetRates("USD"); err == nil {
+		t.Error("Rates of a removed currency should be deleted")
+	}
+	if _, err := manager.GetRates("JPY"); err != nil {
+		t.Errorf("Other workers must keep their rates: %v", err)
+	}
+	if got := manager.GetCurrencies(); len(got) != 1 || got[0] != "JPY" {
+		t.Errorf("GetCurrencies() = %v", got)
+	}
+	if err := manager.RemoveCurrency("USD"); !errors.Is(err, ErrNotTracked) {
+		t.Errorf("Expected ErrNotTracked, got %v", err)
+	}
+
+	if got := fake.CallCount(currencyapitest.EndpointLatest); got != 2 {
+		t.Errorf("Expected one fetch per started worker, got %d", got)
+	}
+}
+
+func TestManagerAddRemoveDerivedCurrency(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"EUR"},
+		FetchInterval: time.Hour,
+		Pivot:         "USD",
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+	waitForRates(t, manager, "EUR", "USD")
+
+	// Added currencies are derived from the last pivot rates without another fetch
+	if err := manager.AddCurrency("GBP"); err != nil {
+		t.Fatalf("AddCurrency() error = %v", err)
+	}
+	rates, err := manager.GetRates("GBP")
+	if err != nil {
+		t.Fatalf("GetRates() error = %v", err)
+	}
+	if !rates.Derived || rates.Pivot != "USD" {
+		t.Errorf("Expected derived GBP rates, got %+v", rates)
+	}
+	if got := fake.CallCount(currencyapitest.EndpointLatest); got != 1 {
+		t.Errorf("Expected a single pivot fetch, got %d", got)
+	}
+
+	if err := manager.RemoveCurrency("USD"); !errors.Is(err, ErrPivotCurrency) {
+		t.Errorf("Expected ErrPivotCurrency, got %v", err)
+	}
+	if err := manager.RemoveCurrency("GBP"); err != nil {
+		t.Fatalf("RemoveCurrency() error = %v", err)
+	}
+	if _, err := manager.GetRates("GBP"); err == nil {
+		t.Error("Rates of a removed currency should be deleted")
+	}
+}
+
+// blockingStore is a memory store whose Set blocks for one currency until released
+type blockingStore struct {
+	*MemoryStore
+	currency string
+	entered  chan struct{}
+	release  chan struct{}
+}
+
+func (s *blockingStore) Set(currency string, data *RateData) error {
+	if currency == s.currency {
+		s.entered <- struct{}{}
+		<-s.release
+	}
+	return s.MemoryStore.Set(currency, data)
+}
+
+func TestManagerAddDerivedCurrencyUnlocked(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	store := &blockingStore{MemoryStore: NewMemoryStore(), currency: "GBP", entered: make(chan struct{}), release: make(chan struct{})}
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"EUR"},
+		FetchInterval: time.Hour,
+		Pivot:         "USD",
+		Store:         store,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+	waitForRates(t, manager, "EUR", "USD")
+
+	added := make(chan error, 1)
+	go func() { added <- manager.AddCurrency("GBP") }()
+	<-store.entered
+
+	// Storing the derived rates must not block the readers of the manager
+	read := make(chan []string, 1)
+	go func() { read <- manager.GetCurrencies() }()
+	select {
+	case currencies := <-read:
+		if !slices.Contains(currencies, "GBP") {
+			t.Errorf("GetCurrencies() = %v, want GBP included", currencies)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetCurrencies() blocked while the derived rates were stored")
+	}
+
+	close(store.release)
+	if err := <-added; err != nil {
+		t.Fatalf("AddCurrency() error = %v", err)
+	}
+	if rates, err := manager.GetRates("GBP"); err != nil || !rates.Derived {
+		t.Errorf("GetRates() = %+v, %v, want derived rates", rates, err)
+	}
+}
+
+func TestManagerSetInterval(t *testing.T) {
+	fake := currencyapitest.NewFakeClient("USD", currencyapitest.DefaultRates())
+	manager, err := NewManager(fake, Config{
+		Currencies:    []string{"USD"},
+		FetchInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.SetInterval(0); err == nil {
+		t.Error("SetInterval(0) should fail")
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer manager.Stop(context.Background())
+
+	if err := manager.SetInterval(10 * time.Millisecond); err != nil {
+		t.Fatalf("SetInterval() error = %v", err)
+	}
+	if got := manager.GetInterval(); got != 10*time.Millisecond {
+		t.Errorf("GetInterval() = %v", got)
+	}
+
+	// The running worker picks up the shorter interval
+	deadline := time.Now().Add(2 * time.Second)
+	for fake.CallCount(currencyapitest.EndpointLatest) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected repeated fetches, got %d", fake.CallCount(currencyapitest.EndpointLatest))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}